@@ -0,0 +1,189 @@
+package language
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// AcceptLanguage is a language range of the Accept-Language header.
+type AcceptLanguage struct {
+	// Tag is a language range as given in the header, "*" for any language.
+	Tag string
+	// Q is a quality value in range 0..1.
+	Q float64
+}
+
+// ParseAcceptLanguage parses value of the Accept-Language header.
+// Ranges are ordered by quality, ranges with equal quality keep the order
+// of the header. Malformed quality values are treated as 0.
+func ParseAcceptLanguage(header string) []AcceptLanguage {
+	var res []AcceptLanguage
+
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		al := AcceptLanguage{Q: 1}
+		params := strings.Split(part, ";")
+		al.Tag = strings.TrimSpace(params[0])
+		if al.Tag == "" {
+			continue
+		}
+
+		for _, p := range params[1:] {
+			p = strings.TrimSpace(p)
+			if !strings.HasPrefix(p, "q=") && !strings.HasPrefix(p, "Q=") {
+				continue
+			}
+			q, err := strconv.ParseFloat(strings.TrimSpace(p[2:]), 64)
+			if err != nil || q < 0 || q > 1 {
+				q = 0
+			}
+			al.Q = q
+		}
+		res = append(res, al)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Q > res[j].Q
+	})
+	return res
+}
+
+// Negotiate returns the language from available which matches the
+// Accept-Language header best. If available is nil all known languages
+// are used (see Supported).
+//
+// A range matches a language with the same code, a range with a region
+// like "de-AT" matches "de" as well, and a range "de" matches "de-CH"
+// if there is no "de". A range "*" matches fallback, or the first available
+// language if fallback is not available. Languages refused with quality 0
+// and languages more specific than them are never returned. If nothing matches, fallback is returned.
+func Negotiate(header string, available []Index, fallback Index) Index {
+	if available == nil {
+		mux.RLock()
+		available = make([]Index, len(languages))
+		for i := range languages {
+			available[i] = Index(i)
+		}
+		mux.RUnlock()
+	}
+
+	ranges := ParseAcceptLanguage(header)
+
+	// a refused range excludes the same and more specific languages only:
+	// "de-CH;q=0" keeps "de" available
+	excluded := make(map[Index]bool)
+	for _, r := range ranges {
+		if r.Q != 0 || r.Tag == "*" {
+			continue
+		}
+		tag := normalizeTag(r.Tag)
+		for _, li := range available {
+			if code := normalizeTag(IndexToCode(li)); code == tag || strings.HasPrefix(code, tag+"-") {
+				excluded[li] = true
+			}
+		}
+	}
+
+	for _, r := range ranges {
+		if r.Q == 0 {
+			break
+		}
+
+		if r.Tag == "*" {
+			if fallback != Unknown && !excluded[fallback] && containsIndex(available, fallback) {
+				return fallback
+			}
+			for _, li := range available {
+				if !excluded[li] {
+					return li
+				}
+			}
+			continue
+		}
+
+		if li := matchRange(r.Tag, available, excluded); li != Unknown {
+			return li
+		}
+	}
+
+	return fallback
+}
+
+// matchRange returns the language from available matching language range tag.
+func matchRange(tag string, available []Index, excluded map[Index]bool) Index {
+	codes := make([]string, len(available))
+	for i, li := range available {
+		codes[i] = normalizeTag(IndexToCode(li))
+	}
+
+	find := func(match func(code string) bool) Index {
+		for i, code := range codes {
+			if !excluded[available[i]] && match(code) {
+				return available[i]
+			}
+		}
+		return Unknown
+	}
+
+	tag = normalizeTag(tag)
+
+	// exact match and truncation: de-at -> de
+	for t := tag; t != ""; t = truncateTag(t) {
+		if li := find(func(code string) bool { return code == t }); li != Unknown {
+			return li
+		}
+	}
+
+	// more specific available language: de -> de-ch
+	return find(func(code string) bool { return strings.HasPrefix(code, tag+"-") })
+}
+
+// normalizeTag converts a language tag to the form used for comparison.
+func normalizeTag(tag string) string {
+	return strings.ToLower(strings.Replace(strings.TrimSpace(tag), "_", "-", -1))
+}
+
+// truncateTag removes the last subtag: "de-at" -> "de", "de" -> "".
+func truncateTag(tag string) string {
+	if i := strings.LastIndex(tag, "-"); i != -1 {
+		return tag[:i]
+	}
+	return ""
+}
+
+func containsIndex(list []Index, li Index) bool {
+	for _, x := range list {
+		if x == li {
+			return true
+		}
+	}
+	return false
+}
+
+// Languages returns languages having translations loaded into the container.
+func (c *Container) Languages() []Index {
+	snap := c.snapshot()
+
+	uniq := make(map[Index]bool, len(snap.sets))
+	res := make([]Index, 0, len(snap.sets))
+	for k := range snap.sets {
+		if !uniq[k.lang] {
+			uniq[k.lang] = true
+			res = append(res, k.lang)
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// Negotiate returns the language of loaded translations matching
+// the Accept-Language header best, the primary language if nothing matches.
+func (c *Container) Negotiate(header string) Index {
+	return Negotiate(header, c.Languages(), c.cfg.primaryLanguage)
+}
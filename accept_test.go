@@ -0,0 +1,94 @@
+package language
+
+import (
+	"testing"
+)
+
+func TestParseAcceptLanguage(t *testing.T) {
+	res := ParseAcceptLanguage("da, en-GB;q=0.8, en;q=0.7, *;q=0.1, fr;q=x,")
+	exp := []AcceptLanguage{
+		{"da", 1},
+		{"en-GB", 0.8},
+		{"en", 0.7},
+		{"*", 0.1},
+		{"fr", 0},
+	}
+
+	if len(res) != len(exp) {
+		t.Fatalf("expected %v, got %v", exp, res)
+	}
+	for i := range exp {
+		if res[i] != exp[i] {
+			t.Errorf("expected %v, got %v", exp[i], res[i])
+		}
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	en, de, sr, fr := ToIndex("en"), ToIndex("de"), ToIndex("sr"), ToIndex("fr-CH")
+	available := []Index{en, de, sr, fr}
+
+	cases := []struct {
+		header   string
+		fallback Index
+		exp      Index
+	}{
+		{"", en, en},
+		{"de", en, de},
+		{"DE", en, de},
+		{"de-AT", en, de},
+		{"de_AT, en;q=0.5", en, de},
+		{"it, sr;q=0.4, de;q=0.9", en, de},
+		{"fr", en, fr},
+		{"it", sr, sr},
+		{"it, *;q=0.1", sr, sr},
+		{"it, *;q=0.1", Unknown, en},
+		{"*, en;q=0", en, de},
+		{"de;q=0, *", de, en},
+		{"it", Unknown, Unknown},
+	}
+
+	for _, tc := range cases {
+		if li := Negotiate(tc.header, available, tc.fallback); li != tc.exp {
+			t.Errorf("%q: expected %s, got %s", tc.header, IndexToCode(tc.exp), IndexToCode(li))
+		}
+	}
+
+	// refused regional variants keep the base language available
+	refused := []struct {
+		header    string
+		available []Index
+		exp       Index
+	}{
+		{"de-CH;q=0, de;q=0.5", []Index{de}, de},
+		{"de-AT;q=0, de", []Index{de}, de},
+		{"fr;q=0, de", []Index{fr, de}, de},
+		{"fr;q=0, *", []Index{fr, en}, en},
+		{"en;q=0, de;q=0, *", []Index{en, de, sr}, sr},
+	}
+	for _, tc := range refused {
+		if li := Negotiate(tc.header, tc.available, Unknown); li != tc.exp {
+			t.Errorf("%q: expected %s, got %s", tc.header, IndexToCode(tc.exp), IndexToCode(li))
+		}
+	}
+}
+
+func TestContainerNegotiate(t *testing.T) {
+	c := New(WithPrimaryLanguage(ToIndex("en")), WithSuffixes("reports", "prj", "reports.prj"))
+	if err := c.AddFileByMask("testdata", "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(c.Languages()); n != 2 {
+		t.Fatalf("expected 2 languages, got %d", n)
+	}
+	if li := c.Negotiate("de-AT,de;q=0.9,en;q=0.8"); li != ToIndex("de") {
+		t.Errorf("expected de, got %s", IndexToCode(li))
+	}
+	if li := c.Negotiate("ru"); li != ToIndex("en") {
+		t.Errorf("expected en, got %s", IndexToCode(li))
+	}
+}
@@ -0,0 +1,131 @@
+package language
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// Bundle is JSON translation of a language returned by ContainerRequest.JSON
+// with its gzip-compressed variant and ETag. Bundles of all loaded languages,
+// domains and namespaces declared by sections are built by ReadRegisteredFiles
+// and rebuilt on reload.
+// A Bundle must not be modified.
+type Bundle struct {
+	JSON []byte
+	// Gzip holds JSON compressed by gzip.
+	Gzip []byte
+	// ETag is a strong entity tag with SHA-256 hash of JSON, quotes included.
+	ETag string
+}
+
+// bundleKey identifies a precomputed bundle.
+type bundleKey struct {
+	lang   Index
+	domain string
+	ns     string
+}
+
+// newBundle creates a bundle of JSON buf compressing it by zw, nil zw is
+// replaced by a new writer.
+func newBundle(buf []byte, zw *gzip.Writer) *Bundle {
+	sum := sha256.Sum256(buf)
+	b := Bundle{
+		JSON: buf,
+		ETag: `"` + hex.EncodeToString(sum[:]) + `"`,
+	}
+
+	var gz bytes.Buffer
+	if zw == nil {
+		zw = gzip.NewWriter(&gz)
+	} else {
+		zw.Reset(&gz)
+	}
+	if _, err := zw.Write(buf); err == nil && zw.Close() == nil {
+		b.Gzip = gz.Bytes()
+	}
+	return &b
+}
+
+// Bundle returns JSON of the request with its gzip variant and ETag.
+// Bundles of loaded languages and of namespaces declared by sections requested
+// with the strategy of the container are precomputed, others are built on
+// every call.
+func (cr ContainerRequest) Bundle() (*Bundle, error) {
+	snap := cr.c.snapshot()
+	if b, ok := cr.bundle(snap); ok {
+		return b, nil
+	}
+
+	buf, err := cr.json(snap, KeyFilter{})
+	if err != nil {
+		return nil, err
+	}
+	return newBundle(buf, nil), nil
+}
+
+// bundle returns precomputed bundle of the request.
+func (cr ContainerRequest) bundle(snap *snapshot) (*Bundle, bool) {
+	if cr.strategy != cr.c.cfg.strategy {
+		return nil, false
+	}
+	b, ok := snap.bundles[bundleKey{
+		lang:   cr.lang,
+		domain: cr.domain,
+		ns:     strings.TrimSuffix(cr.ns, NamespaceSeparator),
+	}]
+	return b, ok
+}
+
+// buildBundles builds bundles of all languages of the snapshot for the base
+// set and every domain, for all keys and every namespace. Items of a language
+// are merged once, namespace bundles take their parts.
+func (c *Container) buildBundles(snap *snapshot) map[bundleKey]*Bundle {
+	langs := make(map[Index]bool)
+	domains := map[string]bool{"": true}
+	for k := range snap.sets {
+		langs[k.lang] = true
+		domains[k.custom] = true
+	}
+
+	res := make(map[bundleKey]*Bundle)
+	zw := gzip.NewWriter(nil)
+	add := func(k bundleKey, kv map[string]ResponseItem) {
+		if buf, err := c.marshalItems(kv); err == nil {
+			res[k] = newBundle(buf, zw)
+		}
+	}
+
+	for li := range langs {
+		for domain := range domains {
+			kv, err := c.Lang(li).Domain(domain).responseMap(snap, KeyFilter{})
+			if err != nil {
+				continue
+			}
+			add(bundleKey{lang: li, domain: domain}, kv)
+
+			parts := make(map[string]map[string]ResponseItem, len(snap.namespaces))
+			for ns := range snap.namespaces {
+				parts[ns] = make(map[string]ResponseItem)
+			}
+			for k, ri := range kv {
+				for i := strings.Index(k, NamespaceSeparator); i > 0; {
+					if part, ok := parts[k[:i]]; ok {
+						part[k[i+len(NamespaceSeparator):]] = ri
+					}
+					next := strings.Index(k[i+len(NamespaceSeparator):], NamespaceSeparator)
+					if next == -1 {
+						break
+					}
+					i += len(NamespaceSeparator) + next
+				}
+			}
+			for ns, part := range parts {
+				add(bundleKey{lang: li, domain: domain, ns: ns}, part)
+			}
+		}
+	}
+	return res
+}
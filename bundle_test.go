@@ -0,0 +1,139 @@
+package language
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func TestBundle(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "en.i18n")
+	if err := os.WriteFile(fname, []byte("Save=Save\n[orders]\nSave=Save order\n[orders.list]\nEmpty=No orders\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "de.i18n"), []byte("Save=Speichern\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	c := New(WithPrimaryLanguage(ToIndex("en")))
+	if err := c.AddFileByMask(dir, "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	de := c.Lang(ToIndex("de"))
+	b, err := de.Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := `{"Save":{"v":"Speichern"},"orders.Save":{"v":"Save order"},"orders.list.Empty":{"v":"No orders"}}`; string(b.JSON) != exp {
+		t.Errorf("expected %s, got %s", exp, b.JSON)
+	}
+	if buf, _ := de.JSON(); !bytes.Equal(buf, b.JSON) {
+		t.Errorf("JSON %s differs from bundle %s", buf, b.JSON)
+	}
+	if b2, _ := de.Bundle(); b2 != b {
+		t.Error("expected precomputed bundle")
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(b.Gzip))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf, err := io.ReadAll(zr); err != nil || !bytes.Equal(buf, b.JSON) {
+		t.Errorf("unexpected gzip content %s (%v)", buf, err)
+	}
+
+	ns, err := de.Namespace("orders").Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := `{"Save":{"v":"Save order"},"list.Empty":{"v":"No orders"}}`; string(ns.JSON) != exp {
+		t.Errorf("expected %s, got %s", exp, ns.JSON)
+	}
+	if ns2, _ := de.Namespace("orders").Bundle(); ns2 != ns {
+		t.Error("expected precomputed namespace bundle")
+	}
+	for _, n := range []string{"orders", "orders.list"} {
+		cr := de.Namespace(n)
+		b, ok := cr.bundle(c.snapshot())
+		if !ok {
+			t.Errorf("%s: expected precomputed bundle", n)
+			continue
+		}
+		if buf, _ := cr.FilteredJSON(KeyFilter{}); !bytes.Equal(buf, b.JSON) {
+			t.Errorf("%s: JSON %s differs from bundle %s", n, buf, b.JSON)
+		}
+	}
+	if _, ok := de.Namespace("missing").bundle(c.snapshot()); ok {
+		t.Error("expected no bundle of unknown namespace")
+	}
+	if b, err := de.Namespace("missing").Bundle(); err != nil || string(b.JSON) != "{}" {
+		t.Errorf("expected empty bundle of unknown namespace, got %v", err)
+	}
+	if ns.ETag == b.ETag || len(ns.ETag) != 66 {
+		t.Errorf("unexpected ETag %s", ns.ETag)
+	}
+
+	// a request with another strategy is built on demand
+	other, err := de.WithStrategy(ReturnEmptyString).Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := `{"Save":{"v":"Speichern"}}`; string(other.JSON) != exp {
+		t.Errorf("expected %s, got %s", exp, other.JSON)
+	}
+
+	// bundles are rebuilt on reload only
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+	if b2, _ := de.Bundle(); b2 == b || b2.ETag != b.ETag {
+		t.Error("expected rebuilt bundle with the same ETag")
+	}
+	if err := os.WriteFile(fname, []byte("Save=Store\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if b2, _ := de.Bundle(); b2.ETag != b.ETag {
+		t.Error("expected bundle not to change before reload")
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+	if b2, _ := de.Bundle(); b2.ETag == b.ETag {
+		t.Error("expected ETag to change after reload")
+	}
+}
+
+func TestBundleIncludedSections(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.i18n":   {Data: []byte("[shop]\n@include cart.i18n\n")},
+		"cart.i18n": {Data: []byte("[items]\nEmpty=Empty cart\n")},
+	}
+	c := New()
+	if err := c.AddFilesFS(fsys, "en.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	cr := c.Lang(ToIndex("en")).Namespace("shop").Namespace("items")
+	b, ok := cr.bundle(c.snapshot())
+	if !ok {
+		t.Fatal("expected precomputed bundle of shop.items")
+	}
+	if exp := `{"Empty":{"v":"Empty cart"}}`; string(b.JSON) != exp {
+		t.Errorf("expected %s, got %s", exp, b.JSON)
+	}
+	if _, ok := c.Lang(ToIndex("en")).Namespace("items").bundle(c.snapshot()); ok {
+		t.Error("expected no bundle of items outside of shop")
+	}
+}
@@ -0,0 +1,703 @@
+package language
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DateStyle is a CLDR length of date and time patterns.
+type DateStyle string
+
+const (
+	DateFull   DateStyle = "full"
+	DateLong   DateStyle = "long"
+	DateMedium DateStyle = "medium"
+	DateShort  DateStyle = "short"
+)
+
+// index returns position of the style in patterns of dateFormat.
+func (ds DateStyle) index() (int, bool) {
+	switch ds {
+	case DateFull:
+		return 0, true
+	case DateLong:
+		return 1, true
+	case DateMedium, "":
+		return 2, true
+	case DateShort:
+		return 3, true
+	}
+	return 0, false
+}
+
+// dateFormat holds CLDR date and time patterns and names of a language.
+type dateFormat struct {
+	// date and time are patterns of full, long, medium and short styles.
+	date [4]string
+	time [4]string
+	// months are used in dates (genitive case in Slavic languages),
+	// standaloneMonths are used alone (nominative case),
+	// empty standaloneMonths means the same names.
+	months           [12]string
+	monthsShort      [12]string
+	standaloneMonths [12]string
+	// weekdays start with Sunday.
+	weekdays      [7]string
+	weekdaysShort [7]string
+	// dayPeriods are AM and PM.
+	dayPeriods [2]string
+}
+
+var (
+	enMonths        = [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"}
+	enMonthsShort   = [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"}
+	enWeekdays      = [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+	enWeekdaysShort = [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"}
+	amPM            = [2]string{"AM", "PM"}
+	time24          = [4]string{"HH:mm:ss zzzz", "HH:mm:ss z", "HH:mm:ss", "HH:mm"}
+)
+
+var dateFormats = map[string]dateFormat{
+	"root": {
+		date:          [4]string{"y MMMM d, EEEE", "y MMMM d", "y MMM d", "y-MM-dd"},
+		time:          time24,
+		months:        enMonths,
+		monthsShort:   enMonthsShort,
+		weekdays:      enWeekdays,
+		weekdaysShort: enWeekdaysShort,
+		dayPeriods:    amPM,
+	},
+	"en": {
+		date:          [4]string{"EEEE, MMMM d, y", "MMMM d, y", "MMM d, y", "M/d/yy"},
+		time:          [4]string{"h:mm:ss" + narrowNbsp + "a zzzz", "h:mm:ss" + narrowNbsp + "a z", "h:mm:ss" + narrowNbsp + "a", "h:mm" + narrowNbsp + "a"},
+		months:        enMonths,
+		monthsShort:   enMonthsShort,
+		weekdays:      enWeekdays,
+		weekdaysShort: enWeekdaysShort,
+		dayPeriods:    amPM,
+	},
+	"en-GB": {
+		date:          [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"},
+		time:          time24,
+		months:        enMonths,
+		monthsShort:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sept", "Oct", "Nov", "Dec"},
+		weekdays:      enWeekdays,
+		weekdaysShort: enWeekdaysShort,
+		dayPeriods:    [2]string{"am", "pm"},
+	},
+	"de": {
+		date:          [4]string{"EEEE, d. MMMM y", "d. MMMM y", "dd.MM.y", "dd.MM.yy"},
+		time:          time24,
+		months:        [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		monthsShort:   [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		weekdays:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		weekdaysShort: [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		dayPeriods:    amPM,
+	},
+	"fr": {
+		date:          [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/y"},
+		time:          time24,
+		months:        [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		monthsShort:   [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		weekdays:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		weekdaysShort: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		dayPeriods:    amPM,
+	},
+	"es": {
+		date:          [4]string{"EEEE, d 'de' MMMM 'de' y", "d 'de' MMMM 'de' y", "d MMM y", "d/M/yy"},
+		time:          [4]string{"H:mm:ss (zzzz)", "H:mm:ss z", "H:mm:ss", "H:mm"},
+		months:        [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		monthsShort:   [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		weekdays:      [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		weekdaysShort: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		dayPeriods:    [2]string{"a." + nbsp + "m.", "p." + nbsp + "m."},
+	},
+	"it": {
+		date:          [4]string{"EEEE d MMMM y", "d MMMM y", "d MMM y", "dd/MM/yy"},
+		time:          time24,
+		months:        [12]string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno", "luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		monthsShort:   [12]string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		weekdays:      [7]string{"domenica", "lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato"},
+		weekdaysShort: [7]string{"dom", "lun", "mar", "mer", "gio", "ven", "sab"},
+		dayPeriods:    amPM,
+	},
+	"ru": {
+		date:             [4]string{"EEEE, d MMMM y 'г'.", "d MMMM y 'г'.", "d MMM y 'г'.", "dd.MM.y"},
+		time:             time24,
+		months:           [12]string{"января", "февраля", "марта", "апреля", "мая", "июня", "июля", "августа", "сентября", "октября", "ноября", "декабря"},
+		monthsShort:      [12]string{"янв.", "февр.", "мар.", "апр.", "мая", "июн.", "июл.", "авг.", "сент.", "окт.", "нояб.", "дек."},
+		standaloneMonths: [12]string{"январь", "февраль", "март", "апрель", "май", "июнь", "июль", "август", "сентябрь", "октябрь", "ноябрь", "декабрь"},
+		weekdays:         [7]string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"},
+		weekdaysShort:    [7]string{"вс", "пн", "вт", "ср", "чт", "пт", "сб"},
+		dayPeriods:       amPM,
+	},
+	"uk": {
+		date:             [4]string{"EEEE, d MMMM y 'р'.", "d MMMM y 'р'.", "d MMM y 'р'.", "dd.MM.yy"},
+		time:             time24,
+		months:           [12]string{"січня", "лютого", "березня", "квітня", "травня", "червня", "липня", "серпня", "вересня", "жовтня", "листопада", "грудня"},
+		monthsShort:      [12]string{"січ.", "лют.", "бер.", "квіт.", "трав.", "черв.", "лип.", "серп.", "вер.", "жовт.", "лист.", "груд."},
+		standaloneMonths: [12]string{"січень", "лютий", "березень", "квітень", "травень", "червень", "липень", "серпень", "вересень", "жовтень", "листопад", "грудень"},
+		weekdays:         [7]string{"неділя", "понеділок", "вівторок", "середа", "четвер", "пʼятниця", "субота"},
+		weekdaysShort:    [7]string{"нд", "пн", "вт", "ср", "чт", "пт", "сб"},
+		dayPeriods:       [2]string{"дп", "пп"},
+	},
+	"pl": {
+		date:             [4]string{"EEEE, d MMMM y", "d MMMM y", "d MMM y", "d.MM.y"},
+		time:             time24,
+		months:           [12]string{"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca", "lipca", "sierpnia", "września", "października", "listopada", "grudnia"},
+		monthsShort:      [12]string{"sty", "lut", "mar", "kwi", "maj", "cze", "lip", "sie", "wrz", "paź", "lis", "gru"},
+		standaloneMonths: [12]string{"styczeń", "luty", "marzec", "kwiecień", "maj", "czerwiec", "lipiec", "sierpień", "wrzesień", "październik", "listopad", "grudzień"},
+		weekdays:         [7]string{"niedziela", "poniedziałek", "wtorek", "środa", "czwartek", "piątek", "sobota"},
+		weekdaysShort:    [7]string{"niedz.", "pon.", "wt.", "śr.", "czw.", "pt.", "sob."},
+		dayPeriods:       amPM,
+	},
+	"cs": {
+		date:             [4]string{"EEEE d. MMMM y", "d. MMMM y", "d. M. y", "dd.MM.yy"},
+		time:             [4]string{"H:mm:ss zzzz", "H:mm:ss z", "H:mm:ss", "H:mm"},
+		months:           [12]string{"ledna", "února", "března", "dubna", "května", "června", "července", "srpna", "září", "října", "listopadu", "prosince"},
+		monthsShort:      [12]string{"led", "úno", "bře", "dub", "kvě", "čvn", "čvc", "srp", "zář", "říj", "lis", "pro"},
+		standaloneMonths: [12]string{"leden", "únor", "březen", "duben", "květen", "červen", "červenec", "srpen", "září", "říjen", "listopad", "prosinec"},
+		weekdays:         [7]string{"neděle", "pondělí", "úterý", "středa", "čtvrtek", "pátek", "sobota"},
+		weekdaysShort:    [7]string{"ne", "po", "út", "st", "čt", "pá", "so"},
+		dayPeriods:       [2]string{"dop.", "odp."},
+	},
+	"sr": {
+		date:          [4]string{"EEEE, dd. MMMM y.", "dd. MMMM y.", "dd.MM.y.", "d.M.yy."},
+		time:          time24,
+		months:        [12]string{"јануар", "фебруар", "март", "април", "мај", "јун", "јул", "август", "септембар", "октобар", "новембар", "децембар"},
+		monthsShort:   [12]string{"јан", "феб", "мар", "апр", "мај", "јун", "јул", "авг", "сеп", "окт", "нов", "дец"},
+		weekdays:      [7]string{"недеља", "понедељак", "уторак", "среда", "четвртак", "петак", "субота"},
+		weekdaysShort: [7]string{"нед", "пон", "уто", "сре", "чет", "пет", "суб"},
+		dayPeriods:    amPM,
+	},
+	"sr-Latn": {
+		date:          [4]string{"EEEE, dd. MMMM y.", "dd. MMMM y.", "dd.MM.y.", "d.M.yy."},
+		time:          time24,
+		months:        [12]string{"januar", "februar", "mart", "april", "maj", "jun", "jul", "avgust", "septembar", "oktobar", "novembar", "decembar"},
+		monthsShort:   [12]string{"jan", "feb", "mar", "apr", "maj", "jun", "jul", "avg", "sep", "okt", "nov", "dec"},
+		weekdays:      [7]string{"nedelja", "ponedeljak", "utorak", "sreda", "četvrtak", "petak", "subota"},
+		weekdaysShort: [7]string{"ned", "pon", "uto", "sre", "čet", "pet", "sub"},
+		dayPeriods:    amPM,
+	},
+}
+
+// findDateFormat returns date format of the language or of its closest parent.
+func findDateFormat(li Index) dateFormat {
+	for tag := IndexToCode(li); tag != ""; tag = parentTag(tag) {
+		if df, ok := dateFormats[tag]; ok {
+			return df
+		}
+	}
+	return dateFormats["root"]
+}
+
+// formatPattern formats t by CLDR date pattern: y, yy, M, MM, MMM, MMMM,
+// L...LLLL (standalone month), d, dd, E...EEEE, a, h, hh, H, HH, m, mm,
+// s, ss and z. Text in apostrophes is copied, two apostrophes are an apostrophe.
+// Time zone is written as abbreviation for all z lengths.
+func (df dateFormat) formatPattern(t time.Time, pattern string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(pattern); {
+		ch := pattern[i]
+
+		if ch == '\'' {
+			if i+1 < len(pattern) && pattern[i+1] == '\'' {
+				sb.WriteByte('\'')
+				i += 2
+				continue
+			}
+			end := strings.IndexByte(pattern[i+1:], '\'')
+			if end == -1 {
+				sb.WriteString(pattern[i+1:])
+				break
+			}
+			sb.WriteString(pattern[i+1 : i+1+end])
+			i += end + 2
+			continue
+		}
+
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z') {
+			sb.WriteByte(ch)
+			i++
+			continue
+		}
+
+		n := 1
+		for i+n < len(pattern) && pattern[i+n] == ch {
+			n++
+		}
+		sb.WriteString(df.formatField(t, ch, n))
+		i += n
+	}
+	return sb.String()
+}
+
+func (df dateFormat) formatField(t time.Time, ch byte, n int) string {
+	switch ch {
+	case 'y':
+		if n == 2 {
+			return pad(t.Year()%100, 2)
+		}
+		return pad(t.Year(), n)
+	case 'M', 'L':
+		m := t.Month() - 1
+		switch {
+		case n <= 2:
+			return pad(int(t.Month()), n)
+		case n == 3:
+			return df.monthsShort[m]
+		case ch == 'L' && df.standaloneMonths[m] != "":
+			return df.standaloneMonths[m]
+		}
+		return df.months[m]
+	case 'd':
+		return pad(t.Day(), n)
+	case 'E':
+		if n == 4 {
+			return df.weekdays[t.Weekday()]
+		}
+		return df.weekdaysShort[t.Weekday()]
+	case 'a':
+		if t.Hour() < 12 {
+			return df.dayPeriods[0]
+		}
+		return df.dayPeriods[1]
+	case 'h':
+		h := t.Hour() % 12
+		if h == 0 {
+			h = 12
+		}
+		return pad(h, n)
+	case 'H':
+		return pad(t.Hour(), n)
+	case 'm':
+		return pad(t.Minute(), n)
+	case 's':
+		return pad(t.Second(), n)
+	case 'z':
+		return t.Format("MST")
+	}
+	return strings.Repeat(string(ch), n)
+}
+
+func pad(x, width int) string {
+	s := strconv.Itoa(x)
+	if len(s) < width {
+		s = strings.Repeat("0", width-len(s)) + s
+	}
+	return s
+}
+
+// FormatDate formats date of t in the language li. Style is one of DateFull,
+// DateLong, DateMedium and DateShort, other values are used as CLDR patterns:
+// DateLong is "October 17, 2026" in English, "17. Oktober 2026" in German
+// and "17 октября 2026 г." in Russian.
+func FormatDate(li Index, t time.Time, style DateStyle) string {
+	df := findDateFormat(li)
+	if i, ok := style.index(); ok {
+		return df.formatPattern(t, df.date[i])
+	}
+	return df.formatPattern(t, string(style))
+}
+
+// FormatTime formats time of t in the language li like FormatDate does for dates:
+// DateShort is "3:04 PM" in English and "15:04" in German.
+func FormatTime(li Index, t time.Time, style DateStyle) string {
+	df := findDateFormat(li)
+	if i, ok := style.index(); ok {
+		return df.formatPattern(t, df.time[i])
+	}
+	return df.formatPattern(t, string(style))
+}
+
+// RelativeUnit is a unit of relative time.
+type RelativeUnit string
+
+const (
+	RelativeSecond RelativeUnit = "second"
+	RelativeMinute RelativeUnit = "minute"
+	RelativeHour   RelativeUnit = "hour"
+	RelativeDay    RelativeUnit = "day"
+	RelativeWeek   RelativeUnit = "week"
+	RelativeMonth  RelativeUnit = "month"
+	RelativeYear   RelativeUnit = "year"
+)
+
+// phrases holds plural forms of a phrase, {0} is replaced by the number.
+type phrases map[PluralCategory]string
+
+type relativePhrases struct {
+	future phrases
+	past   phrases
+}
+
+var relativeTimes = map[string]map[RelativeUnit]relativePhrases{
+	"root": {
+		RelativeSecond: {phrases{PluralOther: "+{0} s"}, phrases{PluralOther: "−{0} s"}},
+		RelativeMinute: {phrases{PluralOther: "+{0} min"}, phrases{PluralOther: "−{0} min"}},
+		RelativeHour:   {phrases{PluralOther: "+{0} h"}, phrases{PluralOther: "−{0} h"}},
+		RelativeDay:    {phrases{PluralOther: "+{0} d"}, phrases{PluralOther: "−{0} d"}},
+		RelativeWeek:   {phrases{PluralOther: "+{0} w"}, phrases{PluralOther: "−{0} w"}},
+		RelativeMonth:  {phrases{PluralOther: "+{0} m"}, phrases{PluralOther: "−{0} m"}},
+		RelativeYear:   {phrases{PluralOther: "+{0} y"}, phrases{PluralOther: "−{0} y"}},
+	},
+	"en": {
+		RelativeSecond: {phrases{PluralOne: "in {0} second", PluralOther: "in {0} seconds"}, phrases{PluralOne: "{0} second ago", PluralOther: "{0} seconds ago"}},
+		RelativeMinute: {phrases{PluralOne: "in {0} minute", PluralOther: "in {0} minutes"}, phrases{PluralOne: "{0} minute ago", PluralOther: "{0} minutes ago"}},
+		RelativeHour:   {phrases{PluralOne: "in {0} hour", PluralOther: "in {0} hours"}, phrases{PluralOne: "{0} hour ago", PluralOther: "{0} hours ago"}},
+		RelativeDay:    {phrases{PluralOne: "in {0} day", PluralOther: "in {0} days"}, phrases{PluralOne: "{0} day ago", PluralOther: "{0} days ago"}},
+		RelativeWeek:   {phrases{PluralOne: "in {0} week", PluralOther: "in {0} weeks"}, phrases{PluralOne: "{0} week ago", PluralOther: "{0} weeks ago"}},
+		RelativeMonth:  {phrases{PluralOne: "in {0} month", PluralOther: "in {0} months"}, phrases{PluralOne: "{0} month ago", PluralOther: "{0} months ago"}},
+		RelativeYear:   {phrases{PluralOne: "in {0} year", PluralOther: "in {0} years"}, phrases{PluralOne: "{0} year ago", PluralOther: "{0} years ago"}},
+	},
+	"de": {
+		RelativeSecond: {phrases{PluralOne: "in {0} Sekunde", PluralOther: "in {0} Sekunden"}, phrases{PluralOne: "vor {0} Sekunde", PluralOther: "vor {0} Sekunden"}},
+		RelativeMinute: {phrases{PluralOne: "in {0} Minute", PluralOther: "in {0} Minuten"}, phrases{PluralOne: "vor {0} Minute", PluralOther: "vor {0} Minuten"}},
+		RelativeHour:   {phrases{PluralOne: "in {0} Stunde", PluralOther: "in {0} Stunden"}, phrases{PluralOne: "vor {0} Stunde", PluralOther: "vor {0} Stunden"}},
+		RelativeDay:    {phrases{PluralOne: "in {0} Tag", PluralOther: "in {0} Tagen"}, phrases{PluralOne: "vor {0} Tag", PluralOther: "vor {0} Tagen"}},
+		RelativeWeek:   {phrases{PluralOne: "in {0} Woche", PluralOther: "in {0} Wochen"}, phrases{PluralOne: "vor {0} Woche", PluralOther: "vor {0} Wochen"}},
+		RelativeMonth:  {phrases{PluralOne: "in {0} Monat", PluralOther: "in {0} Monaten"}, phrases{PluralOne: "vor {0} Monat", PluralOther: "vor {0} Monaten"}},
+		RelativeYear:   {phrases{PluralOne: "in {0} Jahr", PluralOther: "in {0} Jahren"}, phrases{PluralOne: "vor {0} Jahr", PluralOther: "vor {0} Jahren"}},
+	},
+	"fr": {
+		RelativeSecond: {phrases{PluralOne: "dans {0} seconde", PluralOther: "dans {0} secondes"}, phrases{PluralOne: "il y a {0} seconde", PluralOther: "il y a {0} secondes"}},
+		RelativeMinute: {phrases{PluralOne: "dans {0} minute", PluralOther: "dans {0} minutes"}, phrases{PluralOne: "il y a {0} minute", PluralOther: "il y a {0} minutes"}},
+		RelativeHour:   {phrases{PluralOne: "dans {0} heure", PluralOther: "dans {0} heures"}, phrases{PluralOne: "il y a {0} heure", PluralOther: "il y a {0} heures"}},
+		RelativeDay:    {phrases{PluralOne: "dans {0} jour", PluralOther: "dans {0} jours"}, phrases{PluralOne: "il y a {0} jour", PluralOther: "il y a {0} jours"}},
+		RelativeWeek:   {phrases{PluralOne: "dans {0} semaine", PluralOther: "dans {0} semaines"}, phrases{PluralOne: "il y a {0} semaine", PluralOther: "il y a {0} semaines"}},
+		RelativeMonth:  {phrases{PluralOther: "dans {0} mois"}, phrases{PluralOther: "il y a {0} mois"}},
+		RelativeYear:   {phrases{PluralOne: "dans {0} an", PluralOther: "dans {0} ans"}, phrases{PluralOne: "il y a {0} an", PluralOther: "il y a {0} ans"}},
+	},
+	"es": {
+		RelativeSecond: {phrases{PluralOne: "dentro de {0} segundo", PluralOther: "dentro de {0} segundos"}, phrases{PluralOne: "hace {0} segundo", PluralOther: "hace {0} segundos"}},
+		RelativeMinute: {phrases{PluralOne: "dentro de {0} minuto", PluralOther: "dentro de {0} minutos"}, phrases{PluralOne: "hace {0} minuto", PluralOther: "hace {0} minutos"}},
+		RelativeHour:   {phrases{PluralOne: "dentro de {0} hora", PluralOther: "dentro de {0} horas"}, phrases{PluralOne: "hace {0} hora", PluralOther: "hace {0} horas"}},
+		RelativeDay:    {phrases{PluralOne: "dentro de {0} día", PluralOther: "dentro de {0} días"}, phrases{PluralOne: "hace {0} día", PluralOther: "hace {0} días"}},
+		RelativeWeek:   {phrases{PluralOne: "dentro de {0} semana", PluralOther: "dentro de {0} semanas"}, phrases{PluralOne: "hace {0} semana", PluralOther: "hace {0} semanas"}},
+		RelativeMonth:  {phrases{PluralOne: "dentro de {0} mes", PluralOther: "dentro de {0} meses"}, phrases{PluralOne: "hace {0} mes", PluralOther: "hace {0} meses"}},
+		RelativeYear:   {phrases{PluralOne: "dentro de {0} año", PluralOther: "dentro de {0} años"}, phrases{PluralOne: "hace {0} año", PluralOther: "hace {0} años"}},
+	},
+	"it": {
+		RelativeSecond: {phrases{PluralOne: "tra {0} secondo", PluralOther: "tra {0} secondi"}, phrases{PluralOne: "{0} secondo fa", PluralOther: "{0} secondi fa"}},
+		RelativeMinute: {phrases{PluralOne: "tra {0} minuto", PluralOther: "tra {0} minuti"}, phrases{PluralOne: "{0} minuto fa", PluralOther: "{0} minuti fa"}},
+		RelativeHour:   {phrases{PluralOne: "tra {0} ora", PluralOther: "tra {0} ore"}, phrases{PluralOne: "{0} ora fa", PluralOther: "{0} ore fa"}},
+		RelativeDay:    {phrases{PluralOne: "tra {0} giorno", PluralOther: "tra {0} giorni"}, phrases{PluralOne: "{0} giorno fa", PluralOther: "{0} giorni fa"}},
+		RelativeWeek:   {phrases{PluralOne: "tra {0} settimana", PluralOther: "tra {0} settimane"}, phrases{PluralOne: "{0} settimana fa", PluralOther: "{0} settimane fa"}},
+		RelativeMonth:  {phrases{PluralOne: "tra {0} mese", PluralOther: "tra {0} mesi"}, phrases{PluralOne: "{0} mese fa", PluralOther: "{0} mesi fa"}},
+		RelativeYear:   {phrases{PluralOne: "tra {0} anno", PluralOther: "tra {0} anni"}, phrases{PluralOne: "{0} anno fa", PluralOther: "{0} anni fa"}},
+	},
+	"ru": {
+		RelativeSecond: {
+			phrases{PluralOne: "через {0} секунду", PluralFew: "через {0} секунды", PluralMany: "через {0} секунд", PluralOther: "через {0} секунды"},
+			phrases{PluralOne: "{0} секунду назад", PluralFew: "{0} секунды назад", PluralMany: "{0} секунд назад", PluralOther: "{0} секунды назад"},
+		},
+		RelativeMinute: {
+			phrases{PluralOne: "через {0} минуту", PluralFew: "через {0} минуты", PluralMany: "через {0} минут", PluralOther: "через {0} минуты"},
+			phrases{PluralOne: "{0} минуту назад", PluralFew: "{0} минуты назад", PluralMany: "{0} минут назад", PluralOther: "{0} минуты назад"},
+		},
+		RelativeHour: {
+			phrases{PluralOne: "через {0} час", PluralFew: "через {0} часа", PluralMany: "через {0} часов", PluralOther: "через {0} часа"},
+			phrases{PluralOne: "{0} час назад", PluralFew: "{0} часа назад", PluralMany: "{0} часов назад", PluralOther: "{0} часа назад"},
+		},
+		RelativeDay: {
+			phrases{PluralOne: "через {0} день", PluralFew: "через {0} дня", PluralMany: "через {0} дней", PluralOther: "через {0} дня"},
+			phrases{PluralOne: "{0} день назад", PluralFew: "{0} дня назад", PluralMany: "{0} дней назад", PluralOther: "{0} дня назад"},
+		},
+		RelativeWeek: {
+			phrases{PluralOne: "через {0} неделю", PluralFew: "через {0} недели", PluralMany: "через {0} недель", PluralOther: "через {0} недели"},
+			phrases{PluralOne: "{0} неделю назад", PluralFew: "{0} недели назад", PluralMany: "{0} недель назад", PluralOther: "{0} недели назад"},
+		},
+		RelativeMonth: {
+			phrases{PluralOne: "через {0} месяц", PluralFew: "через {0} месяца", PluralMany: "через {0} месяцев", PluralOther: "через {0} месяца"},
+			phrases{PluralOne: "{0} месяц назад", PluralFew: "{0} месяца назад", PluralMany: "{0} месяцев назад", PluralOther: "{0} месяца назад"},
+		},
+		RelativeYear: {
+			phrases{PluralOne: "через {0} год", PluralFew: "через {0} года", PluralMany: "через {0} лет", PluralOther: "через {0} года"},
+			phrases{PluralOne: "{0} год назад", PluralFew: "{0} года назад", PluralMany: "{0} лет назад", PluralOther: "{0} года назад"},
+		},
+	},
+	"uk": {
+		RelativeSecond: {
+			phrases{PluralOne: "через {0} секунду", PluralFew: "через {0} секунди", PluralMany: "через {0} секунд", PluralOther: "через {0} секунди"},
+			phrases{PluralOne: "{0} секунду тому", PluralFew: "{0} секунди тому", PluralMany: "{0} секунд тому", PluralOther: "{0} секунди тому"},
+		},
+		RelativeMinute: {
+			phrases{PluralOne: "через {0} хвилину", PluralFew: "через {0} хвилини", PluralMany: "через {0} хвилин", PluralOther: "через {0} хвилини"},
+			phrases{PluralOne: "{0} хвилину тому", PluralFew: "{0} хвилини тому", PluralMany: "{0} хвилин тому", PluralOther: "{0} хвилини тому"},
+		},
+		RelativeHour: {
+			phrases{PluralOne: "через {0} годину", PluralFew: "через {0} години", PluralMany: "через {0} годин", PluralOther: "через {0} години"},
+			phrases{PluralOne: "{0} годину тому", PluralFew: "{0} години тому", PluralMany: "{0} годин тому", PluralOther: "{0} години тому"},
+		},
+		RelativeDay: {
+			phrases{PluralOne: "через {0} день", PluralFew: "через {0} дні", PluralMany: "через {0} днів", PluralOther: "через {0} дня"},
+			phrases{PluralOne: "{0} день тому", PluralFew: "{0} дні тому", PluralMany: "{0} днів тому", PluralOther: "{0} дня тому"},
+		},
+		RelativeWeek: {
+			phrases{PluralOne: "через {0} тиждень", PluralFew: "через {0} тижні", PluralMany: "через {0} тижнів", PluralOther: "через {0} тижня"},
+			phrases{PluralOne: "{0} тиждень тому", PluralFew: "{0} тижні тому", PluralMany: "{0} тижнів тому", PluralOther: "{0} тижня тому"},
+		},
+		RelativeMonth: {
+			phrases{PluralOne: "через {0} місяць", PluralFew: "через {0} місяці", PluralMany: "через {0} місяців", PluralOther: "через {0} місяця"},
+			phrases{PluralOne: "{0} місяць тому", PluralFew: "{0} місяці тому", PluralMany: "{0} місяців тому", PluralOther: "{0} місяця тому"},
+		},
+		RelativeYear: {
+			phrases{PluralOne: "через {0} рік", PluralFew: "через {0} роки", PluralMany: "через {0} років", PluralOther: "через {0} року"},
+			phrases{PluralOne: "{0} рік тому", PluralFew: "{0} роки тому", PluralMany: "{0} років тому", PluralOther: "{0} року тому"},
+		},
+	},
+	"pl": {
+		RelativeSecond: {
+			phrases{PluralOne: "za {0} sekundę", PluralFew: "za {0} sekundy", PluralMany: "za {0} sekund", PluralOther: "za {0} sekundy"},
+			phrases{PluralOne: "{0} sekundę temu", PluralFew: "{0} sekundy temu", PluralMany: "{0} sekund temu", PluralOther: "{0} sekundy temu"},
+		},
+		RelativeMinute: {
+			phrases{PluralOne: "za {0} minutę", PluralFew: "za {0} minuty", PluralMany: "za {0} minut", PluralOther: "za {0} minuty"},
+			phrases{PluralOne: "{0} minutę temu", PluralFew: "{0} minuty temu", PluralMany: "{0} minut temu", PluralOther: "{0} minuty temu"},
+		},
+		RelativeHour: {
+			phrases{PluralOne: "za {0} godzinę", PluralFew: "za {0} godziny", PluralMany: "za {0} godzin", PluralOther: "za {0} godziny"},
+			phrases{PluralOne: "{0} godzinę temu", PluralFew: "{0} godziny temu", PluralMany: "{0} godzin temu", PluralOther: "{0} godziny temu"},
+		},
+		RelativeDay: {
+			phrases{PluralOne: "za {0} dzień", PluralFew: "za {0} dni", PluralMany: "za {0} dni", PluralOther: "za {0} dnia"},
+			phrases{PluralOne: "{0} dzień temu", PluralFew: "{0} dni temu", PluralMany: "{0} dni temu", PluralOther: "{0} dnia temu"},
+		},
+		RelativeWeek: {
+			phrases{PluralOne: "za {0} tydzień", PluralFew: "za {0} tygodnie", PluralMany: "za {0} tygodni", PluralOther: "za {0} tygodnia"},
+			phrases{PluralOne: "{0} tydzień temu", PluralFew: "{0} tygodnie temu", PluralMany: "{0} tygodni temu", PluralOther: "{0} tygodnia temu"},
+		},
+		RelativeMonth: {
+			phrases{PluralOne: "za {0} miesiąc", PluralFew: "za {0} miesiące", PluralMany: "za {0} miesięcy", PluralOther: "za {0} miesiąca"},
+			phrases{PluralOne: "{0} miesiąc temu", PluralFew: "{0} miesiące temu", PluralMany: "{0} miesięcy temu", PluralOther: "{0} miesiąca temu"},
+		},
+		RelativeYear: {
+			phrases{PluralOne: "za {0} rok", PluralFew: "za {0} lata", PluralMany: "za {0} lat", PluralOther: "za {0} roku"},
+			phrases{PluralOne: "{0} rok temu", PluralFew: "{0} lata temu", PluralMany: "{0} lat temu", PluralOther: "{0} roku temu"},
+		},
+	},
+	"cs": {
+		RelativeSecond: {
+			phrases{PluralOne: "za {0} sekundu", PluralFew: "za {0} sekundy", PluralMany: "za {0} sekundy", PluralOther: "za {0} sekund"},
+			phrases{PluralOne: "před {0} sekundou", PluralFew: "před {0} sekundami", PluralMany: "před {0} sekundy", PluralOther: "před {0} sekundami"},
+		},
+		RelativeMinute: {
+			phrases{PluralOne: "za {0} minutu", PluralFew: "za {0} minuty", PluralMany: "za {0} minuty", PluralOther: "za {0} minut"},
+			phrases{PluralOne: "před {0} minutou", PluralFew: "před {0} minutami", PluralMany: "před {0} minuty", PluralOther: "před {0} minutami"},
+		},
+		RelativeHour: {
+			phrases{PluralOne: "za {0} hodinu", PluralFew: "za {0} hodiny", PluralMany: "za {0} hodiny", PluralOther: "za {0} hodin"},
+			phrases{PluralOne: "před {0} hodinou", PluralFew: "před {0} hodinami", PluralMany: "před {0} hodiny", PluralOther: "před {0} hodinami"},
+		},
+		RelativeDay: {
+			phrases{PluralOne: "za {0} den", PluralFew: "za {0} dny", PluralMany: "za {0} dne", PluralOther: "za {0} dní"},
+			phrases{PluralOne: "před {0} dnem", PluralFew: "před {0} dny", PluralMany: "před {0} dne", PluralOther: "před {0} dny"},
+		},
+		RelativeWeek: {
+			phrases{PluralOne: "za {0} týden", PluralFew: "za {0} týdny", PluralMany: "za {0} týdne", PluralOther: "za {0} týdnů"},
+			phrases{PluralOne: "před {0} týdnem", PluralFew: "před {0} týdny", PluralMany: "před {0} týdne", PluralOther: "před {0} týdny"},
+		},
+		RelativeMonth: {
+			phrases{PluralOne: "za {0} měsíc", PluralFew: "za {0} měsíce", PluralMany: "za {0} měsíce", PluralOther: "za {0} měsíců"},
+			phrases{PluralOne: "před {0} měsícem", PluralFew: "před {0} měsíci", PluralMany: "před {0} měsíce", PluralOther: "před {0} měsíci"},
+		},
+		RelativeYear: {
+			phrases{PluralOne: "za {0} rok", PluralFew: "za {0} roky", PluralMany: "za {0} roku", PluralOther: "za {0} let"},
+			phrases{PluralOne: "před {0} rokem", PluralFew: "před {0} lety", PluralMany: "před {0} roku", PluralOther: "před {0} lety"},
+		},
+	},
+	"sr": {
+		RelativeSecond: {
+			phrases{PluralOne: "за {0} секунду", PluralFew: "за {0} секунде", PluralOther: "за {0} секунди"},
+			phrases{PluralOne: "пре {0} секунде", PluralFew: "пре {0} секунде", PluralOther: "пре {0} секунди"},
+		},
+		RelativeMinute: {
+			phrases{PluralOne: "за {0} минут", PluralFew: "за {0} минута", PluralOther: "за {0} минута"},
+			phrases{PluralOne: "пре {0} минута", PluralFew: "пре {0} минута", PluralOther: "пре {0} минута"},
+		},
+		RelativeHour: {
+			phrases{PluralOne: "за {0} сат", PluralFew: "за {0} сата", PluralOther: "за {0} сати"},
+			phrases{PluralOne: "пре {0} сата", PluralFew: "пре {0} сата", PluralOther: "пре {0} сати"},
+		},
+		RelativeDay: {
+			phrases{PluralOne: "за {0} дан", PluralFew: "за {0} дана", PluralOther: "за {0} дана"},
+			phrases{PluralOne: "пре {0} дана", PluralFew: "пре {0} дана", PluralOther: "пре {0} дана"},
+		},
+		RelativeWeek: {
+			phrases{PluralOne: "за {0} недељу", PluralFew: "за {0} недеље", PluralOther: "за {0} недеља"},
+			phrases{PluralOne: "пре {0} недеље", PluralFew: "пре {0} недеље", PluralOther: "пре {0} недеља"},
+		},
+		RelativeMonth: {
+			phrases{PluralOne: "за {0} месец", PluralFew: "за {0} месеца", PluralOther: "за {0} месеци"},
+			phrases{PluralOne: "пре {0} месеца", PluralFew: "пре {0} месеца", PluralOther: "пре {0} месеци"},
+		},
+		RelativeYear: {
+			phrases{PluralOne: "за {0} годину", PluralFew: "за {0} године", PluralOther: "за {0} година"},
+			phrases{PluralOne: "пре {0} године", PluralFew: "пре {0} године", PluralOther: "пре {0} година"},
+		},
+	},
+	"sr-Latn": {
+		RelativeSecond: {
+			phrases{PluralOne: "za {0} sekundu", PluralFew: "za {0} sekunde", PluralOther: "za {0} sekundi"},
+			phrases{PluralOne: "pre {0} sekunde", PluralFew: "pre {0} sekunde", PluralOther: "pre {0} sekundi"},
+		},
+		RelativeMinute: {
+			phrases{PluralOne: "za {0} minut", PluralFew: "za {0} minuta", PluralOther: "za {0} minuta"},
+			phrases{PluralOne: "pre {0} minuta", PluralFew: "pre {0} minuta", PluralOther: "pre {0} minuta"},
+		},
+		RelativeHour: {
+			phrases{PluralOne: "za {0} sat", PluralFew: "za {0} sata", PluralOther: "za {0} sati"},
+			phrases{PluralOne: "pre {0} sata", PluralFew: "pre {0} sata", PluralOther: "pre {0} sati"},
+		},
+		RelativeDay: {
+			phrases{PluralOne: "za {0} dan", PluralFew: "za {0} dana", PluralOther: "za {0} dana"},
+			phrases{PluralOne: "pre {0} dana", PluralFew: "pre {0} dana", PluralOther: "pre {0} dana"},
+		},
+		RelativeWeek: {
+			phrases{PluralOne: "za {0} nedelju", PluralFew: "za {0} nedelje", PluralOther: "za {0} nedelja"},
+			phrases{PluralOne: "pre {0} nedelje", PluralFew: "pre {0} nedelje", PluralOther: "pre {0} nedelja"},
+		},
+		RelativeMonth: {
+			phrases{PluralOne: "za {0} mesec", PluralFew: "za {0} meseca", PluralOther: "za {0} meseci"},
+			phrases{PluralOne: "pre {0} meseca", PluralFew: "pre {0} meseca", PluralOther: "pre {0} meseci"},
+		},
+		RelativeYear: {
+			phrases{PluralOne: "za {0} godinu", PluralFew: "za {0} godine", PluralOther: "za {0} godina"},
+			phrases{PluralOne: "pre {0} godine", PluralFew: "pre {0} godine", PluralOther: "pre {0} godina"},
+		},
+	},
+}
+
+// findRelativePhrases returns phrases of the unit in the language or in its closest parent.
+func findRelativePhrases(li Index, unit RelativeUnit) (relativePhrases, bool) {
+	for tag := IndexToCode(li); tag != ""; tag = parentTag(tag) {
+		if units, ok := relativeTimes[tag]; ok {
+			rp, ok := units[unit]
+			return rp, ok
+		}
+	}
+	rp, ok := relativeTimes["root"][unit]
+	return rp, ok
+}
+
+// FormatRelativeUnit formats n units of relative time in the language li,
+// negative n is in the past: -3 RelativeDay is "3 days ago" in English,
+// 3 RelativeDay is "через 3 дня" in Russian.
+func FormatRelativeUnit(li Index, n int64, unit RelativeUnit) (string, error) {
+	rp, ok := findRelativePhrases(li, unit)
+	if !ok {
+		return "", fmt.Errorf("unknown relative unit %q", unit)
+	}
+
+	ph := rp.future
+	if n < 0 {
+		ph = rp.past
+		n = -n
+	}
+
+	pc, err := Cardinal(li, n)
+	if err != nil {
+		return "", err
+	}
+	s, ok := ph[pc]
+	if !ok {
+		s = ph[PluralOther]
+	}
+
+	num, err := FormatNumber(li, n)
+	if err != nil {
+		return "", err
+	}
+	return strings.Replace(s, "{0}", num, 1), nil
+}
+
+// relativeUnits are units chosen by FormatRelative, the first unit
+// whose limit exceeds the duration is used. Months have 30 days and years 365 days.
+var relativeUnits = []struct {
+	unit   RelativeUnit
+	length time.Duration
+	limit  time.Duration
+}{
+	{RelativeSecond, time.Second, time.Minute},
+	{RelativeMinute, time.Minute, time.Hour},
+	{RelativeHour, time.Hour, 24 * time.Hour},
+	{RelativeDay, 24 * time.Hour, 7 * 24 * time.Hour},
+	{RelativeWeek, 7 * 24 * time.Hour, 30 * 24 * time.Hour},
+	{RelativeMonth, 30 * 24 * time.Hour, 365 * 24 * time.Hour},
+	{RelativeYear, 365 * 24 * time.Hour, math.MaxInt64},
+}
+
+// FormatRelative formats duration d relative to now in the language li
+// using the largest fitting unit, negative d is in the past:
+// -72h is "3 days ago" in English, "vor 3 Tagen" in German.
+func FormatRelative(li Index, d time.Duration) string {
+	abs := d
+	if abs < 0 {
+		abs = -abs
+	}
+
+	u := relativeUnits[len(relativeUnits)-1]
+	for _, ru := range relativeUnits {
+		if abs < ru.limit {
+			u = ru
+			break
+		}
+	}
+
+	n := int64(math.Round(float64(d) / float64(u.length)))
+	s, err := FormatRelativeUnit(li, n, u.unit)
+	if err != nil {
+		return d.String()
+	}
+	return s
+}
+
+// now returns current time, tests replace it.
+var now = time.Now
+
+// Date formats t by FormatDate in the language of the request.
+func (cr ContainerRequest) Date(t time.Time, style DateStyle) string {
+	return FormatDate(cr.lang, t, style)
+}
+
+// Time formats t by FormatTime in the language of the request.
+func (cr ContainerRequest) Time(t time.Time, style DateStyle) string {
+	return FormatTime(cr.lang, t, style)
+}
+
+// Relative formats d by FormatRelative in the language of the request.
+func (cr ContainerRequest) Relative(d time.Duration) string {
+	return FormatRelative(cr.lang, d)
+}
+
+// RelativeTime formats t relative to now in the language of the request:
+// "in 2 hours", "5 minutes ago".
+func (cr ContainerRequest) RelativeTime(t time.Time) string {
+	return FormatRelative(cr.lang, t.Sub(now()))
+}
+
+// formatDateArg formats argument of MessageFormat {name, date, style},
+// {name, time, style} and {name, relative}. Dates and times accept time.Time,
+// relative accepts time.Time relative to now and time.Duration.
+func formatDateArg(li Index, v interface{}, typ, style string) (string, error) {
+	switch x := v.(type) {
+	case time.Time:
+		switch typ {
+		case "date":
+			return FormatDate(li, x, DateStyle(style)), nil
+		case "time":
+			return FormatTime(li, x, DateStyle(style)), nil
+		case "relative":
+			return FormatRelative(li, x.Sub(now())), nil
+		}
+	case time.Duration:
+		if typ == "relative" {
+			return FormatRelative(li, x), nil
+		}
+	}
+	return "", fmt.Errorf("unsupported %s argument type %T", typ, v)
+}
@@ -0,0 +1,180 @@
+package language
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestFormatDate(t *testing.T) {
+	d := time.Date(2026, time.October, 17, 15, 4, 5, 0, time.UTC)
+
+	cases := []struct {
+		lang  string
+		style DateStyle
+		exp   string
+	}{
+		{"en", DateFull, "Saturday, October 17, 2026"},
+		{"en", DateLong, "October 17, 2026"},
+		{"en", DateMedium, "Oct 17, 2026"},
+		{"en", DateShort, "10/17/26"},
+		{"en-GB", DateShort, "17/10/2026"},
+		{"en-US", DateLong, "October 17, 2026"},
+		{"de", DateLong, "17. Oktober 2026"},
+		{"de", DateFull, "Samstag, 17. Oktober 2026"},
+		{"de-AT", DateMedium, "17.10.2026"},
+		{"ru", DateLong, "17 октября 2026 г."},
+		{"ru", DateMedium, "17 окт. 2026 г."},
+		{"ru", "LLLL y", "октябрь 2026"},
+		{"cs", DateLong, "17. října 2026"},
+		{"pl", DateFull, "sobota, 17 października 2026"},
+		{"es", DateLong, "17 de octubre de 2026"},
+		{"sr", DateLong, "17. октобар 2026."},
+		{"sr-Latn", DateLong, "17. oktobar 2026."},
+		{"xx", DateShort, "2026-10-17"},
+		{"en", "EEE, d MMM ''yy 'at' H:mm", "Sat, 17 Oct '26 at 15:04"},
+	}
+
+	for _, tc := range cases {
+		if s := FormatDate(ToIndex(tc.lang), d, tc.style); s != tc.exp {
+			t.Errorf("%s %s: expected %q, got %q", tc.lang, tc.style, tc.exp, s)
+		}
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	d := time.Date(2026, time.October, 17, 15, 4, 5, 0, time.UTC)
+
+	cases := []struct {
+		lang  string
+		style DateStyle
+		exp   string
+	}{
+		{"en", DateShort, "3:04\u202fPM"},
+		{"en", DateMedium, "3:04:05\u202fPM"},
+		{"en", DateLong, "3:04:05\u202fPM UTC"},
+		{"de", DateShort, "15:04"},
+		{"cs", DateMedium, "15:04:05"},
+		{"ru", DateLong, "15:04:05 UTC"},
+	}
+
+	for _, tc := range cases {
+		if s := FormatTime(ToIndex(tc.lang), d, tc.style); s != tc.exp {
+			t.Errorf("%s %s: expected %q, got %q", tc.lang, tc.style, tc.exp, s)
+		}
+	}
+
+	if s := FormatTime(ToIndex("en"), d.Add(-15*time.Hour), DateShort); s != "12:04\u202fAM" {
+		t.Errorf("expected %q, got %q", "12:04\u202fAM", s)
+	}
+}
+
+func TestFormatRelative(t *testing.T) {
+	cases := []struct {
+		lang string
+		d    time.Duration
+		exp  string
+	}{
+		{"en", -72 * time.Hour, "3 days ago"},
+		{"en", 24 * time.Hour, "in 1 day"},
+		{"en", 90 * time.Minute, "in 2 hours"},
+		{"en", -30 * time.Second, "30 seconds ago"},
+		{"en", 14 * 24 * time.Hour, "in 2 weeks"},
+		{"en", -400 * 24 * time.Hour, "1 year ago"},
+		{"de", -72 * time.Hour, "vor 3 Tagen"},
+		{"ru", 72 * time.Hour, "через 3 дня"},
+		{"ru", -5 * time.Minute, "5 минут назад"},
+		{"ru", 21 * time.Second, "через 21 секунду"},
+		{"pl", -2 * time.Hour, "2 godziny temu"},
+		{"cs", -5 * 24 * time.Hour, "před 5 dny"},
+		{"sr", 2 * time.Hour, "за 2 сата"},
+		{"fr", -60 * 24 * time.Hour, "il y a 2 mois"},
+		{"xx", -48 * time.Hour, "−2 d"},
+	}
+
+	for _, tc := range cases {
+		if s := FormatRelative(ToIndex(tc.lang), tc.d); s != tc.exp {
+			t.Errorf("%s %v: expected %q, got %q", tc.lang, tc.d, tc.exp, s)
+		}
+	}
+
+	if s, err := FormatRelativeUnit(ToIndex("en"), -1, RelativeMonth); err != nil || s != "1 month ago" {
+		t.Errorf("expected %q, got %q (%v)", "1 month ago", s, err)
+	}
+	if _, err := FormatRelativeUnit(ToIndex("en"), 1, "decade"); err == nil {
+		t.Error("expected error for unknown unit")
+	}
+}
+
+func TestDateArguments(t *testing.T) {
+	fixed := time.Date(2026, time.October, 17, 12, 0, 0, 0, time.UTC)
+	now = func() time.Time { return fixed }
+	defer func() { now = time.Now }()
+
+	fsys := fstest.MapFS{
+		"en.i18n": {Data: []byte(strings.Join([]string{
+			"Updated=Updated {when, relative}",
+			"Shipped=Shipped on {day, date, long} at {day, time, short}",
+			"Expires=Expires {left, relative}",
+		}, "\n"))},
+		"ru.i18n": {Data: []byte("Shipped=Отправлено {day, date, long}\n")},
+	}
+
+	c := New(WithPrimaryLanguage(ToIndex("en")), WithMessageFormat())
+	if err := c.AddFileByMaskFS(fsys, ".", "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	type m = map[string]interface{}
+	cases := []struct {
+		lang string
+		id   string
+		args m
+		exp  string
+	}{
+		{"en", "Updated", m{"when": fixed.Add(-3 * time.Hour)}, "Updated 3 hours ago"},
+		{"de", "Updated", m{"when": fixed.Add(-3 * time.Hour)}, "Updated vor 3 Stunden"},
+		{"en", "Expires", m{"left": 48 * time.Hour}, "Expires in 2 days"},
+		{"en", "Shipped", m{"day": fixed}, "Shipped on October 17, 2026 at 12:00\u202fPM"},
+		{"ru", "Shipped", m{"day": fixed}, "Отправлено 17 октября 2026 г."},
+		{"en", "Expires", m{"left": "soon"}, "Expires soon"},
+	}
+
+	for _, tc := range cases {
+		cr := c.Lang(ToIndex(tc.lang))
+		if s, err := cr.Format(tc.id, tc.args); err != nil || s != tc.exp {
+			t.Errorf("%s.%s: expected %q, got %q (%v)", tc.lang, tc.id, tc.exp, s, err)
+		}
+	}
+
+	cr := c.Lang(ToIndex("de"))
+	if s := cr.Date(fixed, DateLong); s != "17. Oktober 2026" {
+		t.Errorf("expected %q, got %q", "17. Oktober 2026", s)
+	}
+	if s := cr.RelativeTime(fixed.Add(time.Hour)); s != "in 1 Stunde" {
+		t.Errorf("expected %q, got %q", "in 1 Stunde", s)
+	}
+
+	if _, err := compileMessage("{t, relative, short}"); err == nil {
+		t.Error("expected error for relative style")
+	}
+
+	// placeholders without MessageFormat
+	c = New(WithPrimaryLanguage(ToIndex("en")))
+	plain := fstest.MapFS{"en.i18n": {Data: []byte("Due=Due {when}\n")}}
+	if err := c.AddFilesFS(plain, "en.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+	for lang, exp := range map[string]string{"de": "Due 17.10.2026", "ru": "Due 17 окт. 2026 г."} {
+		if s, err := c.Lang(ToIndex(lang)).Format("Due", m{"when": fixed}); err != nil || s != exp {
+			t.Errorf("%s: expected %q, got %q (%v)", lang, exp, s, err)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package language
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// Diagnostic describes a problem found in a translation file.
+type Diagnostic struct {
+	File string
+	// Line and Column are 1-based, Column counts characters.
+	Line   int
+	Column int
+	Msg    string
+}
+
+func (d Diagnostic) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s", d.File, d.Line, d.Column, d.Msg)
+}
+
+// ParseErrors is returned by ReadRegisteredFiles of a container created with
+// WithStrictMode. It holds all problems found in all files in order of reading.
+type ParseErrors []Diagnostic
+
+func (pe ParseErrors) Error() string {
+	var sb strings.Builder
+	for i := range pe {
+		if i > 0 {
+			sb.WriteByte('\n')
+		}
+		sb.WriteString(pe[i].Error())
+	}
+	return sb.String()
+}
+
+// byteOrderMark is allowed only at the beginning of a file.
+const byteOrderMark = "\ufeff"
+
+// column returns 1-based character column of byte offset pos in line.
+func column(line string, pos int) int {
+	if pos > len(line) {
+		pos = len(line)
+	}
+	return utf8.RuneCountInString(line[:pos]) + 1
+}
+
+// invalidUTF8 returns byte offset of the first invalid UTF-8 sequence in s, -1 if s is valid.
+func invalidUTF8(s string) int {
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i
+		}
+		i += size
+	}
+	return -1
+}
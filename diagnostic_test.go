@@ -0,0 +1,80 @@
+package language
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestStrictMode(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.i18n": {Data: []byte(strings.Join([]string{
+			"\ufeffSave=Save",
+			"Cancel",
+			"  =Nothing",
+			"Save=Keep",
+			"Exit=Ex\xffit",
+			"Help=Press \ufeffF1",
+			"Liked={count, plural, one {# like}}",
+			"Mail={",
+			"Dear customer,",
+			"} signature",
+			"Body={",
+			"never closed",
+		}, "\n"))},
+		"de.i18n": {Data: []byte("Save=Speichern\nSave=Sichern\n")},
+	}
+
+	c := New(WithPrimaryLanguage(ToIndex("en")), WithMessageFormat(), WithStrictMode())
+	if err := c.AddFileByMaskFS(fsys, ".", "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := c.ReadRegisteredFiles()
+	var pe ParseErrors
+	if !errors.As(err, &pe) {
+		t.Fatalf("expected ParseErrors, got %v", err)
+	}
+
+	exp := []string{
+		"en.i18n:2:1: malformed line: '=' expected",
+		"en.i18n:3:3: empty key",
+		`en.i18n:4:1: duplicate key "Save", first defined at line 1`,
+		"en.i18n:5:8: invalid UTF-8",
+		"en.i18n:6:12: byte order mark in the middle of file",
+		`en.i18n:7:23: Liked: plural argument "count" has no case other`,
+		`en.i18n:10:1: Mail: unexpected text "signature" after multiline value`,
+		"en.i18n:11:6: Body: unterminated multiline value",
+		`de.i18n:2:1: duplicate key "Save", first defined at line 1`,
+	}
+	var got []string
+	for _, d := range pe {
+		got = append(got, d.Error())
+	}
+	if strings.Join(got, "\n") != strings.Join(exp, "\n") {
+		t.Errorf("expected:\n%s\ngot:\n%s", strings.Join(exp, "\n"), strings.Join(got, "\n"))
+	}
+	if err.Error() != strings.Join(got, "\n") {
+		t.Errorf("unexpected error text %q", err.Error())
+	}
+	if n := len(c.snapshot().sets); n != 0 {
+		t.Errorf("expected translations not to be replaced, got %d sets", n)
+	}
+
+	// without strict mode problems are skipped, later duplicates win
+	c = New(WithPrimaryLanguage(ToIndex("en")))
+	if err := c.AddFilesFS(fsys, "de.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+	cr := c.Lang(ToIndex("de"))
+	if v := cr.Value("Save"); v != "Sichern" {
+		t.Errorf("expected %q, got %q", "Sichern", v)
+	}
+	if set := c.snapshot().sets[key{lang: ToIndex("de")}]; len(set.items) != 1 {
+		t.Errorf("expected 1 item, got %d", len(set.items))
+	}
+}
@@ -0,0 +1,224 @@
+package language
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ArgumentError reports placeholders of a translated value having no
+// argument and arguments having no placeholder.
+type ArgumentError struct {
+	Key string
+	// Missing holds names of placeholders without arguments.
+	Missing []string
+	// Extra holds names of map arguments not used by placeholders.
+	// Unused struct fields are not reported.
+	Extra []string
+}
+
+func (e *ArgumentError) Error() string {
+	var s []string
+	if len(e.Missing) > 0 {
+		s = append(s, "missing arguments: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Extra) > 0 {
+		s = append(s, "extra arguments: "+strings.Join(e.Extra, ", "))
+	}
+	return e.Key + ": " + strings.Join(s, "; ")
+}
+
+// args provides access to placeholder arguments.
+type args struct {
+	values map[string]interface{}
+	// strict is true if all values are expected to be used.
+	strict bool
+}
+
+// get returns argument by name. Struct fields are matched case-insensitively.
+func (a args) get(name string) (interface{}, bool) {
+	if v, ok := a.values[name]; ok || a.strict {
+		return v, ok
+	}
+	for n, v := range a.values {
+		if strings.EqualFold(n, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+// newArgs converts map with string keys or struct to args.
+// Struct fields are named by tag i18n or by field name.
+func newArgs(v interface{}) (args, error) {
+	res := args{values: make(map[string]interface{})}
+	if v == nil {
+		return res, nil
+	}
+
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return res, nil
+		}
+		rv = rv.Elem()
+	}
+
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return res, fmt.Errorf("unsupported argument type %T", v)
+		}
+		res.strict = true
+		iter := rv.MapRange()
+		for iter.Next() {
+			res.values[iter.Key().String()] = iter.Value().Interface()
+		}
+	case reflect.Struct:
+		rt := rv.Type()
+		for i := 0; i < rt.NumField(); i++ {
+			f := rt.Field(i)
+			if f.PkgPath != "" {
+				// unexported
+				continue
+			}
+			name := f.Name
+			if tag, ok := f.Tag.Lookup("i18n"); ok {
+				if tag == "-" {
+					continue
+				}
+				if tag != "" {
+					name = tag
+				}
+			}
+			res.values[name] = rv.Field(i).Interface()
+		}
+	default:
+		return res, fmt.Errorf("unsupported argument type %T", v)
+	}
+	return res, nil
+}
+
+// interpolate replaces placeholders {name} in s by arguments formatted
+// for the language li. Placeholders without arguments stay intact. A brace
+// not starting a placeholder is copied as is.
+func interpolate(s string, li Index, a args) (string, []string, []string) {
+	var (
+		sb      strings.Builder
+		missing []string
+		used    = make(map[string]bool)
+	)
+
+	for {
+		from := strings.IndexByte(s, '{')
+		if from == -1 {
+			sb.WriteString(s)
+			break
+		}
+		to := strings.IndexByte(s[from:], '}')
+		if to == -1 {
+			sb.WriteString(s)
+			break
+		}
+		to += from
+
+		name := s[from+1 : to]
+		if !isPlaceholderName(name) {
+			sb.WriteString(s[:from+1])
+			s = s[from+1:]
+			continue
+		}
+
+		sb.WriteString(s[:from])
+		if v, ok := a.get(name); ok {
+			sb.WriteString(formatArg(li, v, "", ""))
+			used[name] = true
+		} else {
+			sb.WriteString(s[from : to+1])
+			missing = appendUniqueString(missing, name)
+		}
+		s = s[to+1:]
+	}
+
+	return sb.String(), missing, unusedArgs(a, used)
+}
+
+// unusedArgs returns sorted names of map arguments not found in used.
+func unusedArgs(a args, used map[string]bool) []string {
+	if !a.strict {
+		return nil
+	}
+
+	var res []string
+	for name := range a.values {
+		if !used[name] {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+func isPlaceholderName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if !(r == '_' || r == '.' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func appendUniqueString(list []string, s string) []string {
+	for _, x := range list {
+		if x == s {
+			return list
+		}
+	}
+	return append(list, s)
+}
+
+// Format returns translation of id with placeholders like {name} replaced
+// by arguments. Arguments are given by a map with string keys or by a struct,
+// where a placeholder refers a field by tag `i18n:"name"` or by field name
+// ignoring case. Numbers and Money are formatted according to the requested
+// language.
+//
+// If the container is created with WithMessageFormat, the value is rendered
+// as ICU MessageFormat. Plural rules of the language the value is found in
+// are applied, numbers are formatted according to the requested language.
+//
+// Placeholders without arguments stay in the result as is. In that case,
+// and if map holds keys not used by placeholders, *ArgumentError is returned
+// together with the result.
+//
+// If id is not found the result depends on the request strategy.
+func (cr ContainerRequest) Format(id string, v interface{}) (string, error) {
+	item, li, ok := cr.findItem(cr.c.snapshot(), id)
+	if !ok {
+		return cr.notFound(id), nil
+	}
+
+	a, err := newArgs(v)
+	if err != nil {
+		return item.Value, err
+	}
+
+	var (
+		res            string
+		missing, extra []string
+	)
+	if item.msg != nil {
+		res, missing, extra = item.msg.render(li, cr.lang, a)
+	} else {
+		res, missing, extra = interpolate(item.Value, cr.lang, a)
+	}
+
+	if len(missing) > 0 || len(extra) > 0 {
+		return res, &ArgumentError{Key: id, Missing: missing, Extra: extra}
+	}
+	return res, nil
+}
@@ -0,0 +1,88 @@
+package language
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFormat(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.i18n": {Data: []byte("Hello=Hello, {name}! You have {count} new messages.\nBrace={ not a placeholder }\n")},
+	}
+
+	c := New()
+	if err := c.AddFilesFS(fsys, "en.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+	cr := c.Lang(ToIndex("en"))
+
+	type user struct {
+		Name     string
+		Messages int    `i18n:"count"`
+		Password string `i18n:"-"`
+		age      int
+	}
+
+	cases := []struct {
+		title   string
+		id      string
+		args    interface{}
+		exp     string
+		missing []string
+		extra   []string
+	}{
+		{"Map", "Hello", map[string]interface{}{"name": "Bob", "count": 3}, "Hello, Bob! You have 3 new messages.", nil, nil},
+		{"Struct", "Hello", user{Name: "Ann", Messages: 1}, "Hello, Ann! You have 1 new messages.", nil, nil},
+		{"StructPtr", "Hello", &user{Name: "Ann", Messages: 1}, "Hello, Ann! You have 1 new messages.", nil, nil},
+		{"Missing", "Hello", map[string]string{"name": "Bob"}, "Hello, Bob! You have {count} new messages.", []string{"count"}, nil},
+		{"Extra", "Hello", map[string]interface{}{"name": "Bob", "count": 3, "age": 7}, "Hello, Bob! You have 3 new messages.", nil, []string{"age"}},
+		{"Nil", "Hello", nil, "Hello, {name}! You have {count} new messages.", []string{"name", "count"}, nil},
+		{"NotPlaceholder", "Brace", nil, "{ not a placeholder }", nil, nil},
+		{"NotFound", "Bye", nil, "Bye" + NotFoundMarker, nil, nil},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.title, func(t *testing.T) {
+			s, err := cr.Format(tc.id, tc.args)
+			if s != tc.exp {
+				t.Errorf("expected '%s', got '%s'", tc.exp, s)
+			}
+
+			var ae *ArgumentError
+			if tc.missing == nil && tc.extra == nil {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.As(err, &ae) {
+				t.Fatalf("expected *ArgumentError, got %v", err)
+			}
+			if !reflect.DeepEqual(ae.Missing, tc.missing) || !reflect.DeepEqual(ae.Extra, tc.extra) {
+				t.Errorf("expected missing %v, extra %v, got %v", tc.missing, tc.extra, err)
+			}
+		})
+	}
+
+	if _, err := cr.Format("Hello", 42); err == nil {
+		t.Error("expected error for unsupported arguments")
+	}
+
+	buf, err := cr.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kv map[string]ResponseItem
+	if err := json.Unmarshal(buf, &kv); err != nil {
+		t.Fatal(err)
+	}
+	if v := kv["Hello"].Value; v != "Hello, {name}! You have {count} new messages." {
+		t.Errorf("expected placeholders in JSON, got '%s'", v)
+	}
+}
@@ -0,0 +1,162 @@
+package language
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// AutoLanguage in a path of Handler selects the language by Accept-Language header.
+const AutoLanguage = "auto"
+
+// HandlerOption defines options of Handler.
+type HandlerOption struct {
+	cacheControl string
+	domain       string
+}
+
+// WithCacheControl assigns value of Cache-Control header of responses.
+// Responses are revalidated by ETag on every request by default.
+func WithCacheControl(value string) func(o *HandlerOption) {
+	return func(o *HandlerOption) {
+		o.cacheControl = value
+	}
+}
+
+// WithHandlerDomain makes the handler serve bundles of the domain assigned by WithDomains.
+func WithHandlerDomain(domain string) func(o *HandlerOption) {
+	return func(o *HandlerOption) {
+		o.domain = domain
+	}
+}
+
+type handler struct {
+	c   *Container
+	cfg HandlerOption
+}
+
+// Handler returns http.Handler serving bundles of the container: /en.json
+// holds all keys of English, /en/orders.json holds keys of namespace orders.
+// Language "auto" is negotiated from Accept-Language header of the request.
+// Unknown languages and namespaces are not found. Responses are gzip-compressed
+// if the client accepts it, If-None-Match is answered by 304 Not Modified.
+// Use http.StripPrefix to serve bundles under a path prefix.
+func (c *Container) Handler(fn ...func(o *HandlerOption)) http.Handler {
+	h := handler{
+		c: c,
+		cfg: HandlerOption{
+			cacheControl: "no-cache",
+		},
+	}
+	for _, f := range fn {
+		f(&h.cfg)
+	}
+	return &h
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	p := strings.TrimPrefix(r.URL.Path, "/")
+	if !strings.HasSuffix(p, ".json") {
+		http.NotFound(w, r)
+		return
+	}
+	p = strings.TrimSuffix(p, ".json")
+
+	code, ns := p, ""
+	if i := strings.IndexByte(p, '/'); i != -1 {
+		code, ns = p[:i], strings.Replace(p[i+1:], "/", NamespaceSeparator, -1)
+	}
+
+	li := Unknown
+	if code == AutoLanguage {
+		w.Header().Add("Vary", "Accept-Language")
+		li = h.c.Negotiate(r.Header.Get("Accept-Language"))
+	} else if li = Parse(code); !containsIndex(h.c.Languages(), li) {
+		li = Unknown
+	}
+	if li == Unknown {
+		http.NotFound(w, r)
+		return
+	}
+
+	cr := h.c.Lang(li).Domain(h.cfg.domain).Namespace(ns)
+	b, ok := cr.bundle(h.c.snapshot())
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	body, etag := b.JSON, b.ETag
+	gz := b.Gzip != nil && acceptsGzip(r.Header.Get("Accept-Encoding"))
+	if gz {
+		body, etag = b.Gzip, gzipETag(b.ETag)
+	}
+
+	hdr := w.Header()
+	hdr.Add("Vary", "Accept-Encoding")
+	hdr.Set("ETag", etag)
+	hdr.Set("Content-Language", IndexToCode(li))
+	if h.cfg.cacheControl != "" {
+		hdr.Set("Cache-Control", h.cfg.cacheControl)
+	}
+
+	if matchETag(r.Header.Get("If-None-Match"), b.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	hdr.Set("Content-Type", "application/json; charset=utf-8")
+	if gz {
+		hdr.Set("Content-Encoding", "gzip")
+	}
+	hdr.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(body)
+	}
+}
+
+// gzipETag returns ETag of gzip variant of the bundle having etag.
+func gzipETag(etag string) string {
+	return strings.TrimSuffix(etag, `"`) + `-gzip"`
+}
+
+// matchETag returns true if If-None-Match header value matches etag or
+// ETag of its gzip variant. Weak comparison is used.
+func matchETag(header, etag string) bool {
+	for _, t := range strings.Split(header, ",") {
+		t = strings.TrimPrefix(strings.TrimSpace(t), "W/")
+		if t == "*" || t == etag || t == gzipETag(etag) {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsGzip returns true if Accept-Encoding header value allows gzip.
+func acceptsGzip(header string) bool {
+	for _, e := range strings.Split(header, ",") {
+		name, q := e, ""
+		if i := strings.IndexByte(e, ';'); i != -1 {
+			name, q = e[:i], e[i+1:]
+		}
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name != "gzip" && name != "*" {
+			continue
+		}
+		q = strings.TrimSpace(q)
+		if strings.HasPrefix(q, "q=") {
+			if f, err := strconv.ParseFloat(q[2:], 64); err == nil && f == 0 {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package language
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHandler(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.i18n": {Data: []byte("Save=Save\n[orders.list]\nEmpty=No orders\n")},
+		"de.i18n": {Data: []byte("Save=Speichern\n")},
+	}
+	c := New(WithPrimaryLanguage(ToIndex("en")))
+	if err := c.AddFilesFS(fsys, "en.i18n", "de.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	h := c.Handler(WithCacheControl("public, max-age=60"))
+	serve := func(method, path string, hdr map[string]string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, nil)
+		for k, v := range hdr {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	de, err := c.Lang(ToIndex("de")).Bundle()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	w := serve(http.MethodGet, "/de.json", nil)
+	if w.Code != http.StatusOK || !bytes.Equal(w.Body.Bytes(), de.JSON) {
+		t.Fatalf("unexpected response %d %s", w.Code, w.Body)
+	}
+	exp := map[string]string{
+		"Content-Type":     "application/json; charset=utf-8",
+		"Content-Language": "de",
+		"Cache-Control":    "public, max-age=60",
+		"ETag":             de.ETag,
+	}
+	for k, v := range exp {
+		if got := w.Header().Get(k); got != v {
+			t.Errorf("%s: expected %q, got %q", k, v, got)
+		}
+	}
+
+	w = serve(http.MethodGet, "/de/orders/list.json", nil)
+	if exp := `{"Empty":{"v":"No orders"}}`; w.Code != http.StatusOK || w.Body.String() != exp {
+		t.Errorf("expected %s, got %d %s", exp, w.Code, w.Body)
+	}
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		hdr    map[string]string
+		code   int
+	}{
+		{"UnknownLanguage", http.MethodGet, "/fr.json", nil, http.StatusNotFound},
+		{"InvalidLanguage", http.MethodGet, "/xx-yy-zz.json", nil, http.StatusNotFound},
+		{"UnknownNamespace", http.MethodGet, "/de/missing.json", nil, http.StatusNotFound},
+		{"NoSuffix", http.MethodGet, "/de", nil, http.StatusNotFound},
+		{"Method", http.MethodPost, "/de.json", nil, http.StatusMethodNotAllowed},
+		{"NotModified", http.MethodGet, "/de.json", map[string]string{"If-None-Match": `"x", W/` + de.ETag}, http.StatusNotModified},
+		{"Modified", http.MethodGet, "/de.json", map[string]string{"If-None-Match": `"x"`}, http.StatusOK},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if w := serve(tc.method, tc.path, tc.hdr); w.Code != tc.code {
+				t.Errorf("expected %d, got %d", tc.code, w.Code)
+			}
+		})
+	}
+
+	t.Run("Auto", func(t *testing.T) {
+		w := serve(http.MethodGet, "/auto.json", map[string]string{"Accept-Language": "fr, de-AT;q=0.8"})
+		if w.Code != http.StatusOK || w.Header().Get("Content-Language") != "de" {
+			t.Errorf("expected de, got %d %q", w.Code, w.Header().Get("Content-Language"))
+		}
+		if v := w.Header().Values("Vary"); len(v) != 2 || v[0] != "Accept-Language" {
+			t.Errorf("unexpected Vary %q", v)
+		}
+		w = serve(http.MethodGet, "/auto.json", nil)
+		if w.Header().Get("Content-Language") != "en" {
+			t.Errorf("expected primary language, got %q", w.Header().Get("Content-Language"))
+		}
+	})
+
+	t.Run("Gzip", func(t *testing.T) {
+		w := serve(http.MethodGet, "/de.json", map[string]string{"Accept-Encoding": "br, gzip"})
+		if w.Header().Get("Content-Encoding") != "gzip" || w.Header().Get("ETag") == de.ETag {
+			t.Fatalf("unexpected headers %v", w.Header())
+		}
+		zr, err := gzip.NewReader(w.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if buf, err := io.ReadAll(zr); err != nil || !bytes.Equal(buf, de.JSON) {
+			t.Errorf("unexpected content %s (%v)", buf, err)
+		}
+
+		etag := w.Header().Get("ETag")
+		w = serve(http.MethodGet, "/de.json", map[string]string{"If-None-Match": etag})
+		if w.Code != http.StatusNotModified {
+			t.Errorf("expected %d, got %d", http.StatusNotModified, w.Code)
+		}
+
+		w = serve(http.MethodGet, "/de.json", map[string]string{"Accept-Encoding": "gzip;q=0"})
+		if w.Header().Get("Content-Encoding") != "" {
+			t.Error("expected identity encoding")
+		}
+	})
+
+	t.Run("Head", func(t *testing.T) {
+		w := serve(http.MethodHead, "/de.json", nil)
+		if w.Code != http.StatusOK || w.Body.Len() != 0 || w.Header().Get("Content-Length") == "" {
+			t.Errorf("unexpected response %d %q %v", w.Code, w.Body, w.Header())
+		}
+	})
+}
@@ -1,27 +1,43 @@
 package language
 
 import (
-	"bufio"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unicode/utf8"
 )
 
 var (
-	FileExtension  = ".i18n"
-	HintSeparator  = "//"
-	NotFoundMarker = "\u2638"
+	FileExtension = ".i18n"
+	HintSeparator = "//"
+	// NamespaceSeparator joins section name and key: [orders] Save=... is orders.Save.
+	NamespaceSeparator = "."
+	NotFoundMarker     = "\u2638"
 )
 
+// RequestStrategy defines what ContainerRequest returns if a key is not found
+// in the requested language and in the languages of its fallback chain.
 type RequestStrategy int8
 
 const (
+	// ReturnNotFoundVariable returns the key followed by NotFoundMarker.
 	ReturnNotFoundVariable RequestStrategy = iota
+
+	// ReturnEmptyString returns empty string.
 	ReturnEmptyString
+
+	// ReturnInPrimaryLanguage returns the item in the primary language.
+	// If it's not found there as well, the key followed by NotFoundMarker is returned.
 	ReturnInPrimaryLanguage
 )
 
@@ -29,6 +45,7 @@ type key struct {
 	lang Index
 	// custom suffix en.{custom}.i18n
 	// empty for default resource file.
+	// Keys of translation sets hold the domain, empty for the base set.
 	custom string
 }
 
@@ -36,6 +53,23 @@ type file struct {
 	key
 	name     string
 	fullName string // path + file name
+	fsys     fs.FS  // nil for files on disk
+}
+
+// open opens the file from the file system it was registered from.
+func (f *file) open() (io.ReadCloser, error) {
+	if f.fsys == nil {
+		return os.Open(f.fullName)
+	}
+	return f.fsys.Open(f.fullName)
+}
+
+// stat returns file info from the file system it was registered from.
+func (f *file) stat() (fs.FileInfo, error) {
+	if f.fsys == nil {
+		return os.Stat(f.fullName)
+	}
+	return fs.Stat(f.fsys, f.fullName)
 }
 
 // Item represents a row in a .i18n file.
@@ -43,12 +77,21 @@ type Item struct {
 	Key   string
 	Value string
 	Hint  string
+
+	// msg holds Value compiled as ICU MessageFormat, nil if
+	// the container is created without WithMessageFormat.
+	msg message
 }
 
 // ResponseItem represents a row to be returned to the client.
 type ResponseItem struct {
 	Value string `json:"v"`
 	Hint  string `json:"h,omitempty"`
+	// Plural holds plural forms by category, Value holds the form "other"
+	// if the key without category is not declared.
+	Plural map[PluralCategory]string `json:"p,omitempty"`
+	// Ordinal holds ordinal forms by category.
+	Ordinal map[PluralCategory]string `json:"o,omitempty"`
 }
 
 // Set holds a set of items.
@@ -57,12 +100,36 @@ type Set struct {
 	index map[string]int // key -> index in Items
 }
 
+// snapshot holds all translation sets read by a single ReadRegisteredFiles call.
+// A snapshot is never modified after it has been published.
+type snapshot struct {
+	sets map[key]Set
+	// namespaces holds names of all sections and their parents.
+	namespaces map[string]bool
+	// bundles holds JSON precomputed for languages, domains and namespaces.
+	bundles map[bundleKey]*Bundle
+}
+
+// lookup returns the set by key.
+func (s *snapshot) lookup(k key) (Set, bool) {
+	set, ok := s.sets[k]
+	return set, ok
+}
+
 // Container stores for all translation resources.
 type Container struct {
-	cfg          Option
-	translations map[key]Set
-	files        []file
-	customDirs   []string
+	cfg Option
+
+	// translations holds current *snapshot.
+	translations atomic.Value
+
+	// mu guards files, customDirs, included and watcher and serialises reloads.
+	mu         sync.Mutex
+	files      []file
+	customDirs []string
+	// included holds files included by the last read files, they are watched as well.
+	included []file
+	watcher  *watcher
 }
 
 // Option defines options for Container.
@@ -70,10 +137,34 @@ type Option struct {
 	// Default language
 	primaryLanguage Index
 
+	// fallbacks holds fallback chains configured by WithFallback.
+	fallbacks map[Index][]Index
+
 	// suffixPriority maps text and it's priority
 	suffixPriority map[string]int
 
 	bracketSymbol string
+
+	// messageFormat enables compilation of values as ICU MessageFormat.
+	messageFormat bool
+
+	// strict enables collecting of all problems found in files.
+	strict bool
+
+	// strategy defines result of requests of missing keys.
+	strategy RequestStrategy
+
+	// missingKeyHandler is called when a key is not found.
+	missingKeyHandler func(lang Index, key string)
+
+	// watchInterval defines how often ListenFileChange checks files.
+	watchInterval time.Duration
+
+	// reloadErrorHandler receives errors of reloads started by ListenFileChange.
+	reloadErrorHandler func(error)
+
+	// domains holds suffixes read into separate translation sets.
+	domains map[string]bool
 }
 
 // WithPrimaryLanguage assigns a primary language.
@@ -83,6 +174,19 @@ func WithPrimaryLanguage(li Index) func(o *Option) {
 	}
 }
 
+// WithFallback assigns a chain of languages used if a key is not found in the language li.
+// The chain replaces fallback to parent languages: WithFallback(ToIndex("sr-Latn"),
+// ToIndex("sr"), ToIndex("hr")) makes "sr-Latn" -> "sr" -> "hr" -> primary language.
+//
+// Languages without a configured chain fall back to their parents, then to the
+// primary language: "de-AT" -> "de" -> primary. If a parent has a configured chain
+// it's followed: "sr-Latn-RS" -> "sr-Latn" -> "sr" -> "hr" -> primary.
+func WithFallback(li Index, chain ...Index) func(o *Option) {
+	return func(o *Option) {
+		o.fallbacks[li] = chain
+	}
+}
+
 // WithSuffixes assigns suffixes of translation files in the order of applying priority.
 // The first suffix has the highest priority.
 func WithSuffixes(suffix ...string) func(o *Option) {
@@ -95,6 +199,22 @@ func WithSuffixes(suffix ...string) func(o *Option) {
 	}
 }
 
+// WithDomains makes files with listed suffixes separate domains instead of
+// overrides of the base files: en.reports.i18n holds domain reports requested
+// by ContainerRequest.Domain("reports"). Other suffixes still override, so
+// en.reports.prj.i18n overrides domain reports and en.prj.i18n overrides
+// the base files. Keys missing in a domain are looked up in the base files.
+func WithDomains(domain ...string) func(o *Option) {
+	return func(o *Option) {
+		if o.domains == nil {
+			o.domains = make(map[string]bool)
+		}
+		for _, d := range domain {
+			o.domains[d] = true
+		}
+	}
+}
+
 // WithBrackets assignss wrapping symbol used by .
 func WithBrackets(bracketSymbol string) func(o *Option) {
 	return func(o *Option) {
@@ -102,16 +222,76 @@ func WithBrackets(bracketSymbol string) func(o *Option) {
 	}
 }
 
+// WithMessageFormat enables ICU MessageFormat syntax in values: {name},
+// {count, plural, one {...} other {...}}, {gender, select, ...} and nested
+// arguments. Values are compiled while files are read, ReadRegisteredFiles
+// returns syntax errors with file name and line number.
+// A multiline value which is a single argument with its braces, like
+// {count, plural, ... } spread over lines, keeps the braces.
+// ContainerRequest.Format renders compiled values.
+func WithMessageFormat() func(o *Option) {
+	return func(o *Option) {
+		o.messageFormat = true
+	}
+}
+
+// WithStrictMode makes ReadRegisteredFiles report every problem found in
+// files: malformed lines, empty and duplicate keys, unterminated multiline
+// values, invalid UTF-8, byte order marks in the middle of a file and
+// MessageFormat syntax errors. Problems are returned as ParseErrors and
+// translations are not replaced if there are any.
+func WithStrictMode() func(o *Option) {
+	return func(o *Option) {
+		o.strict = true
+	}
+}
+
+// WithRequestStrategy assigns the strategy applied to missing keys.
+// ReturnInPrimaryLanguage is used by default.
+func WithRequestStrategy(rs RequestStrategy) func(o *Option) {
+	return func(o *Option) {
+		o.strategy = rs
+	}
+}
+
+// WithMissingKeyHandler assigns a function called every time a key is not found
+// neither in the requested language nor in the languages used as a fallback.
+// The function is called concurrently and must be safe for concurrent use.
+func WithMissingKeyHandler(fn func(lang Index, key string)) func(o *Option) {
+	return func(o *Option) {
+		o.missingKeyHandler = fn
+	}
+}
+
+// WithWatchInterval assigns how often ListenFileChange checks registered files for changes.
+func WithWatchInterval(d time.Duration) func(o *Option) {
+	return func(o *Option) {
+		o.watchInterval = d
+	}
+}
+
+// WithReloadErrorHandler assigns a function receiving errors of reloads
+// initiated by ListenFileChange. Translations loaded before stay in use
+// if reload fails. Without the handler errors are written to the standard logger.
+func WithReloadErrorHandler(fn func(error)) func(o *Option) {
+	return func(o *Option) {
+		o.reloadErrorHandler = fn
+	}
+}
+
 // New creates a new translations container.
 func New(fn ...func(o *Option)) *Container {
 	c := Container{
-		translations: make(map[key]Set),
 		cfg: Option{
 			primaryLanguage: -1, // option is not set
+			fallbacks:       make(map[Index][]Index),
 			suffixPriority:  make(map[string]int),
+			strategy:        ReturnInPrimaryLanguage,
+			watchInterval:   2 * time.Second,
 		},
 	}
 	c.cfg.suffixPriority[""] = 0
+	c.translations.Store(&snapshot{sets: make(map[key]Set)})
 
 	for _, f := range fn {
 		f(&c.cfg)
@@ -122,6 +302,9 @@ func New(fn ...func(o *Option)) *Container {
 // AddFiles registers .i18n files in the container.
 // Returns error if even one could not be found or it's a directory.
 func (c *Container) AddFiles(filenames ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	for _, filename := range filenames {
 		fi, err := os.Stat(filename)
 		if err != nil {
@@ -155,18 +338,72 @@ func parseFileInfo(fi fs.FileInfo) (file, error) {
 
 // AddFileByMask registers .i18n files matching mask from the path specified by path.
 func (c *Container) AddFileByMask(dir string, mask string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	dirEntries, err := os.ReadDir(dir)
 	if err != nil {
 		return err
 	}
 
+	return c.addDirEntries(nil, dir, dirEntries, mask)
+}
+
+// AddFilesFS registers .i18n files located in the file system fsys,
+// for example embed.FS. File names must be valid fs.FS paths.
+//
+// Files of the same language and suffix are applied in the order of registration,
+// so files registered later override files registered earlier. It allows to put
+// an embedded base bundle under files on disk.
+func (c *Container) AddFilesFS(fsys fs.FS, filenames ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, filename := range filenames {
+		fi, err := fs.Stat(fsys, filename)
+		if err != nil {
+			return err
+		}
+
+		pfi, err := parseFileInfo(fi)
+		if err != nil {
+			return err
+		}
+		pfi.fullName = filename
+		pfi.fsys = fsys
+		c.files = append(c.files, pfi)
+	}
+	return nil
+}
+
+// AddFileByMaskFS registers .i18n files matching mask from the directory dir
+// of the file system fsys. Use "." as dir for the root of fsys.
+func (c *Container) AddFileByMaskFS(fsys fs.FS, dir string, mask string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dirEntries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return err
+	}
+
+	return c.addDirEntries(fsys, dir, dirEntries, mask)
+}
+
+// addDirEntries registers files from dirEntries matching mask.
+// Caller must hold c.mu.
+func (c *Container) addDirEntries(fsys fs.FS, dir string, dirEntries []fs.DirEntry, mask string) error {
 	for _, de := range dirEntries {
 		if de.IsDir() {
 			continue
 		}
 
 		if len(mask) > 0 && mask != "*" {
-			ok, err := filepath.Match(mask, de.Name())
+			match := path.Match
+			if fsys == nil {
+				match = filepath.Match
+			}
+			ok, err := match(mask, de.Name())
 			if err != nil {
 				return err
 			}
@@ -184,14 +421,28 @@ func (c *Container) AddFileByMask(dir string, mask string) error {
 		if err != nil {
 			return err
 		}
-		pfi.fullName = filepath.Join(dir, pfi.name)
+		if fsys == nil {
+			pfi.fullName = filepath.Join(dir, pfi.name)
+		} else {
+			pfi.fullName = path.Join(dir, pfi.name)
+			pfi.fsys = fsys
+		}
 		c.files = append(c.files, pfi)
 	}
 	return nil
 }
 
 // AddCustomDir registers a directory with custom translation files.
+//
+// Files named by the same rule <lang>[.<suffix>].i18n are looked up in custom
+// directories every time ReadRegisteredFiles is called. They are applied on top of
+// registered files: an item from a custom directory replaces the item with the same
+// key. Custom directories are applied in the order of registration, files of a
+// custom directory are applied in the order of suffix priority.
 func (c *Container) AddCustomDir(dirs ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
 	for _, d := range dirs {
 		f, err := os.Stat(d)
 		if err != nil {
@@ -205,36 +456,141 @@ func (c *Container) AddCustomDir(dirs ...string) error {
 	return nil
 }
 
-func (c *Container) ListenFileChange() error {
-	return nil
+// sortFilesBySuffixPriority orders files by language and suffix priority.
+// Files with equal language and suffix keep the order of registration.
+func (c *Container) sortFilesBySuffixPriority(files []file) {
+	sort.SliceStable(files, func(i, j int) bool {
+		if files[i].lang == files[j].lang {
+			return c.suffixRank(files[i].custom) < c.suffixRank(files[j].custom)
+		}
+		return files[i].lang < files[j].lang
+	})
+}
+
+// suffixRank returns priority of the suffix. A suffix not assigned by
+// WithSuffixes has priority of its part following the domain.
+func (c *Container) suffixRank(suffix string) int {
+	if p, ok := c.cfg.suffixPriority[suffix]; ok {
+		return p
+	}
+	_, override := c.splitDomain(suffix)
+	return c.cfg.suffixPriority[override]
 }
 
-func (c *Container) sortFilesBySuffixPriority() {
-	sort.Slice(c.files, func(i, j int) bool {
-		if c.files[i].lang == c.files[j].lang {
-			return c.cfg.suffixPriority[c.files[i].custom] < c.cfg.suffixPriority[c.files[j].custom]
+// splitDomain splits file suffix into domain assigned by WithDomains and
+// the rest: "reports.prj" is domain "reports" and override "prj".
+func (c *Container) splitDomain(suffix string) (domain, override string) {
+	first := suffix
+	if i := strings.Index(suffix, "."); i != -1 {
+		first = suffix[:i]
+	}
+	if !c.cfg.domains[first] {
+		return "", suffix
+	}
+	return first, strings.TrimPrefix(suffix[len(first):], ".")
+}
+
+// customDirFiles returns translation files found in the directory
+// ordered by suffix priority.
+func (c *Container) customDirFiles(dir string) ([]file, error) {
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var res []file
+	for _, de := range dirEntries {
+		if de.IsDir() || !strings.HasSuffix(de.Name(), FileExtension) {
+			continue
 		}
-		return c.files[i].lang < c.files[j].lang
-	})
+
+		fi, err := de.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		pfi, err := parseFileInfo(fi)
+		if err != nil {
+			return nil, err
+		}
+		pfi.fullName = filepath.Join(dir, pfi.name)
+		res = append(res, pfi)
+	}
+
+	c.sortFilesBySuffixPriority(res)
+	return res, nil
 }
 
-// ReadRegisteredFiles reads content of all registered files and stores items in the container.
+// layers returns registered files followed by files from custom directories
+// in the order they have to be applied. Caller must hold c.mu.
+func (c *Container) layers() ([]file, error) {
+	c.sortFilesBySuffixPriority(c.files)
+
+	res := make([]file, len(c.files))
+	copy(res, c.files)
+
+	for _, dir := range c.customDirs {
+		files, err := c.customDirFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, files...)
+	}
+	return res, nil
+}
+
+// ReadRegisteredFiles reads content of all registered files and files from
+// custom directories and stores items in the container. Files included by
+// other files are not treated as translations of a language.
+// All files are read into a new snapshot which replaces the current one at once,
+// therefore concurrent requests never see partially loaded translations.
+// If any file could not be read, translations loaded before stay in use.
 func (c *Container) ReadRegisteredFiles() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	files, err := c.layers()
+	if err != nil {
+		return err
+	}
 
-	c.sortFilesBySuffixPriority()
+	var (
+		diags ParseErrors
+		ls    loadState
+	)
+	defer func() {
+		c.included = ls.included
+	}()
+
+	loaded := make([][]Item, len(files))
+	errs := make([]error, len(files))
+	for i := range files {
+		loaded[i], errs[i] = c.loadFile(&files[i], &ls)
+	}
+
+	translations := make(map[key]Set)
+	for i := range files {
+		if ls.includes(&files[i]) {
+			// a shared file, it is not a translation of its own
+			continue
+		}
 
-	for i := range c.files {
-		items, err := c.loadFile(c.files[i].fullName)
+		items, err := loaded[i], errs[i]
+		if pe, ok := err.(ParseErrors); ok {
+			diags = append(diags, pe...)
+			continue
+		}
 		if err != nil {
 			return err
 		}
 
+		domain, _ := c.splitDomain(files[i].custom)
 		key := key{
-			lang:   c.files[i].lang,
-			custom: "",
+			lang:   files[i].lang,
+			custom: domain,
 		}
 
-		if ti, ok := c.translations[key]; ok {
+		if ti, ok := translations[key]; ok {
 			// replace
 			for j := range items {
 				if idx, ok := ti.index[items[j].Key]; ok {
@@ -246,19 +602,32 @@ func (c *Container) ReadRegisteredFiles() error {
 			}
 			// important to assign back, because ti is a copy,
 			// and ti.items can refer to another address.
-			c.translations[key] = ti
+			translations[key] = ti
 		} else {
 			x := Set{index: make(map[string]int)}
 			x.items = items
 			for i, item := range items {
 				x.index[item.Key] = i
 			}
-			c.translations[key] = x
+			translations[key] = x
 		}
 	}
+
+	if len(diags) > 0 {
+		return diags
+	}
+
+	snap := &snapshot{sets: translations, namespaces: ls.namespaces}
+	snap.bundles = c.buildBundles(snap)
+	c.translations.Store(snap)
 	return nil
 }
 
+// snapshot returns current translations. The returned snapshot must not be modified.
+func (c *Container) snapshot() *snapshot {
+	return c.translations.Load().(*snapshot)
+}
+
 func parseFileName(filename string) (li Index, suffix string) {
 	from := strings.Index(filename, ".")
 	to := strings.LastIndex(filename, ".")
@@ -274,122 +643,422 @@ func parseFileName(filename string) (li Index, suffix string) {
 	return ToIndex(filename[0:from]), filename[from+1 : to]
 }
 
-func (c *Container) loadFile(filename string) ([]Item, error) {
-	f, err := os.Open(filename)
+// loadFile reads items of the file and of files included by it. Later items
+// replace earlier items with the same key, items of the file replace
+// included items. In strict mode problems of the file are returned
+// as ParseErrors along with items read.
+func (c *Container) loadFile(fi *file, ls *loadState) ([]Item, error) {
+	f, err := fi.open()
 	if err != nil {
 		return nil, err
 	}
 	defer f.Close()
 
-	scanner := bufio.NewScanner(f)
+	ls.stack = append(ls.stack, fi.fullName)
+	defer func() {
+		ls.stack = ls.stack[:len(ls.stack)-1]
+	}()
+
+	r := newLineReader(f)
+
+	var (
+		res   []Item
+		index = make(map[string]int)
+		// lines holds line numbers of items.
+		lines []int
+		diags ParseErrors
+		// prefix is the namespace of the current section.
+		prefix string
+		// included holds items of included files.
+		included      []Item
+		includedIndex = make(map[string]int)
+	)
+	report := func(lineNo, col int, format string, args ...interface{}) {
+		diags = append(diags, Diagnostic{File: fi.fullName, Line: lineNo, Column: col, Msg: fmt.Sprintf(format, args...)})
+	}
+
+	if c.cfg.strict {
+		r.check = func(lineNo int, line string) {
+			if pos := invalidUTF8(line); pos != -1 {
+				report(lineNo, column(line, pos), "invalid UTF-8")
+			}
+			if pos := strings.Index(line, byteOrderMark); pos != -1 {
+				report(lineNo, column(line, pos), "byte order mark in the middle of file")
+			}
+		}
+	}
 
-	var res []Item
 	for {
-		if !scanner.Scan() {
+		raw, ok := r.next()
+		if !ok {
 			break
 		}
+		lineNo := r.start
 
-		if err := scanner.Err(); err != nil {
-			return nil, err
+		line := strings.TrimLeft(raw, " ")
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		indent := len(raw) - len(line)
+
+		if name, ok := includePath(line); ok {
+			outer := ls.prefix
+			ls.prefix += prefix
+			items, err := c.loadInclude(fi, name, ls)
+			ls.prefix = outer
+			if pe, ok := err.(ParseErrors); ok {
+				diags = append(diags, pe...)
+				continue
+			}
+			if err != nil {
+				if !c.cfg.strict {
+					return nil, fmt.Errorf("%s:%d: %w", fi.fullName, lineNo, err)
+				}
+				report(lineNo, indent+1, "%s", err)
+				continue
+			}
+			for _, item := range items {
+				item.Key = prefix + item.Key
+				if idx, ok := includedIndex[item.Key]; ok {
+					included[idx] = item
+					continue
+				}
+				includedIndex[item.Key] = len(included)
+				included = append(included, item)
+			}
+			continue
 		}
 
-		line := scanner.Text()
-		line = strings.TrimLeft(line, " ")
-		if len(line) == 0 || line[0] == '#' {
+		if name, ok := sectionName(line); ok {
+			if c.cfg.strict && !isSectionName(name) {
+				report(lineNo, indent+1, "invalid section name %q", name)
+			}
+			prefix = ""
+			if name != "" {
+				prefix = name + NamespaceSeparator
+				ls.addNamespace(ls.prefix + name)
+			}
 			continue
 		}
 
-		item := c.parseLine(line)
-		if item != nil {
-			res = append(res, *item)
+		k, v, ok := splitLine(line)
+		if !ok {
+			if c.cfg.strict {
+				report(lineNo, indent+1, "malformed line: '=' expected")
+			}
+			continue
 		}
+		if k == "" && c.cfg.strict {
+			report(lineNo, indent+1, "empty key")
+		}
+
+		var item *Item
+		valuePos := len(raw) - len(strings.TrimLeft(v, " \t"))
+		if opensBlock(strings.TrimSpace(v)) {
+			value, rest, ok := readBlock(r, strings.TrimSpace(v))
+			if !ok {
+				if !c.cfg.strict {
+					return nil, fmt.Errorf("%s:%d: %s: unterminated multiline value", fi.fullName, lineNo, k)
+				}
+				report(lineNo, column(raw, valuePos), "%s: unterminated multiline value", k)
+				break
+			}
+			item = &Item{Key: k, Value: unescapeValue(k, value)}
+			if c.cfg.messageFormat && isMessageArgument("{"+item.Value+"}") {
+				// Files={count, plural, ... } keeps its braces
+				item.Value = "{" + item.Value + "}"
+			}
+			rest = strings.TrimSpace(rest)
+			switch {
+			case strings.HasPrefix(rest, HintSeparator):
+				item.Hint = unescape(strings.TrimSpace(rest[len(HintSeparator):]))
+			case rest != "" && c.cfg.strict:
+				report(r.lineNo, 1, "%s: unexpected text %q after multiline value", k, rest)
+			}
+		} else {
+			item = c.parseLine(line)
+		}
+		item.Key = prefix + item.Key
+
+		if c.cfg.messageFormat {
+			if item.msg, err = compileMessage(item.Value); err != nil {
+				if !c.cfg.strict {
+					return nil, fmt.Errorf("%s:%d: %s: %w", fi.fullName, lineNo, item.Key, err)
+				}
+				col := column(raw, valuePos)
+				var me *msgError
+				if errors.As(err, &me) && me.Offset <= len(item.Value) && !strings.Contains(item.Value, "\n") {
+					col += utf8.RuneCountInString(item.Value[:me.Offset])
+					err = errors.New(me.Msg)
+				}
+				report(lineNo, col, "%s: %s", item.Key, err)
+			}
+			if item.msg == nil {
+				item.msg = message{}
+			}
+		}
+
+		if idx, ok := index[item.Key]; ok {
+			if c.cfg.strict {
+				report(lineNo, indent+1, "duplicate key %q, first defined at line %d", item.Key, lines[idx])
+			}
+			res[idx] = *item
+			lines[idx] = lineNo
+			continue
+		}
+		index[item.Key] = len(res)
+		res = append(res, *item)
+		lines = append(lines, lineNo)
 	}
 
+	if err := r.err(); err != nil {
+		return nil, fmt.Errorf("%s: %w", fi.fullName, err)
+	}
+
+	for _, item := range included {
+		if _, ok := index[item.Key]; !ok {
+			res = append(res, item)
+		}
+	}
+
+	if len(diags) > 0 {
+		return res, diags
+	}
 	return res, nil
 }
 
+// loadInclude reads the file name included by fi.
+func (c *Container) loadInclude(fi *file, name string, ls *loadState) ([]Item, error) {
+	if name == "" {
+		return nil, errors.New("include path expected")
+	}
+
+	inc, err := fi.include(name)
+	if err != nil {
+		return nil, err
+	}
+	if chain := ls.cycle(inc.fullName); chain != "" {
+		return nil, fmt.Errorf("include cycle: %s", chain)
+	}
+	ls.included = append(ls.included, inc)
+
+	items, err := c.loadFile(&inc, ls)
+	if _, ok := err.(ParseErrors); !ok && err != nil {
+		return nil, fmt.Errorf("include %s: %w", name, err)
+	}
+	return items, err
+}
+
+// sectionName returns name of section header [name], empty name resets the section.
+// A line holding '=' is not a section header.
+func sectionName(line string) (string, bool) {
+	line = strings.TrimSpace(line)
+	if len(line) < 2 || line[0] != '[' || line[len(line)-1] != ']' || strings.Contains(line, "=") {
+		return "", false
+	}
+	return strings.TrimSpace(line[1 : len(line)-1]), true
+}
+
+// isSectionName returns true if name has no spaces and brackets.
+func isSectionName(name string) bool {
+	return !strings.ContainsAny(name, " \t[]")
+}
+
+// splitLine splits line at the first '=' not escaped by a backslash into
+// trimmed and unescaped key and raw value.
+func splitLine(line string) (string, string, bool) {
+	vx := indexUnescaped(line, "=")
+	if vx == -1 {
+		return "", "", false
+	}
+	return unescape(strings.TrimSpace(line[:vx])), line[vx+1:], true
+}
+
 func (c *Container) parseLine(line string) *Item {
 	var res Item
 
-	vx := strings.Index(line, "=")
-	if vx == -1 {
+	key, val, ok := splitLine(line)
+	if !ok {
 		return nil
 	}
 
-	res.Key = strings.TrimSpace(line[0:vx])
-	val := strings.TrimSpace(line[vx+1:])
-	hx := strings.Index(val, HintSeparator)
+	res.Key = key
+	hx := hintIndex(val)
 	if hx != -1 {
-		res.Hint = strings.TrimSpace(val[hx+len(HintSeparator):])
-		res.Value = strings.TrimSpace(val[0:hx])
-	} else {
-		res.Value = val
+		res.Hint = unescape(strings.TrimSpace(val[hx+len(HintSeparator):]))
+		val = val[0:hx]
 	}
+	res.Value = unescapeValue(key, strings.TrimSpace(val))
 
 	return &res
 }
 
+// ContainerRequest provides access to translations of a single language.
 type ContainerRequest struct {
-	lang Index
-	c    *Container
+	lang     Index
+	strategy RequestStrategy
+	// ns is the namespace prefix of keys ending with NamespaceSeparator.
+	ns string
+	// domain is the domain looked up before the base set.
+	domain string
+	c      *Container
 }
 
+// Lang returns request of translations in the language li.
 func (c *Container) Lang(li Index) ContainerRequest {
 	return ContainerRequest{
-		lang: li,
-		c:    c,
+		lang:     li,
+		strategy: c.cfg.strategy,
+		c:        c,
+	}
+}
+
+// WithStrategy returns a copy of the request using strategy rs for missing keys
+// instead of the strategy assigned to the container.
+func (cr ContainerRequest) WithStrategy(rs RequestStrategy) ContainerRequest {
+	cr.strategy = rs
+	return cr
+}
+
+// Domain returns a copy of the request looking up keys in the domain assigned
+// by WithDomains first and in the base files of the same language then.
+func (cr ContainerRequest) Domain(domain string) ContainerRequest {
+	cr.domain = domain
+	return cr
+}
+
+// sets returns keys of translation sets of the language li in the order of lookup.
+func (cr ContainerRequest) sets(li Index) []key {
+	if cr.domain == "" {
+		return []key{{lang: li}}
 	}
+	return []key{{lang: li, custom: cr.domain}, {lang: li}}
 }
 
-func (c *ContainerRequest) Value(id string) string {
-	res, ok := c.item(id)
+// Namespace returns a copy of the request looking up keys in the namespace ns
+// declared by section [ns]: Namespace("orders").Value("Save") returns orders.Save.
+// Namespaces are nested by repeated calls. JSON of the request holds keys of
+// the namespace without the prefix.
+func (cr ContainerRequest) Namespace(ns string) ContainerRequest {
+	if ns != "" {
+		cr.ns += ns + NamespaceSeparator
+	}
+	return cr
+}
+
+// Value returns translation of id. If id is not found the result
+// depends on the request strategy.
+func (cr ContainerRequest) Value(id string) string {
+	res, ok := cr.item(cr.c.snapshot(), id)
 	if !ok {
-		return id + NotFoundMarker
+		return cr.notFound(id)
 	}
 	return res.Value
 }
 
-func (c *ContainerRequest) Hint(id string) string {
-	res, ok := c.item(id)
+// notFound returns value of the missing key id according to the request strategy.
+func (cr ContainerRequest) notFound(id string) string {
+	if cr.strategy == ReturnEmptyString {
+		return ""
+	}
+	return id + NotFoundMarker
+}
+
+// Hint returns hint of id, empty string if id is not found.
+func (cr ContainerRequest) Hint(id string) string {
+	res, ok := cr.item(cr.c.snapshot(), id)
 	if !ok {
 		return ""
 	}
 	return res.Hint
 }
 
-func (cr *ContainerRequest) item(id string) (Item, bool) {
+// chain returns languages to look up a key in, starting with the requested one.
+// The primary language ends the chain if the strategy is ReturnInPrimaryLanguage.
+func (cr ContainerRequest) chain() []Index {
+	res := []Index{cr.lang}
+	for li := cr.lang; ; {
+		if fc, ok := cr.c.cfg.fallbacks[li]; ok {
+			for _, x := range fc {
+				res = appendUniqueIndex(res, x)
+			}
+			break
+		}
+		if li = Parent(li); li == Unknown {
+			break
+		}
+		res = appendUniqueIndex(res, li)
+	}
+
+	if cr.strategy == ReturnInPrimaryLanguage && cr.c.cfg.primaryLanguage != Unknown {
+		res = appendUniqueIndex(res, cr.c.cfg.primaryLanguage)
+	}
+	return res
+}
+
+func appendUniqueIndex(list []Index, li Index) []Index {
+	if containsIndex(list, li) {
+		return list
+	}
+	return append(list, li)
+}
+
+// item looks up id in the snapshot following the fallback chain. Callers pass
+// a snapshot loaded once per call to get consistent results during reloads.
+func (cr ContainerRequest) item(snap *snapshot, id string) (Item, bool) {
+	item, _, ok := cr.findItem(snap, id)
+	return item, ok
+}
 
+// findItem looks up id like item does and returns the language the item is found in.
+func (cr ContainerRequest) findItem(snap *snapshot, id string) (Item, Index, bool) {
+	id = cr.ns + cr.trimID(id)
+
+	for _, li := range cr.chain() {
+		if item, ok := cr.lookup(snap, li, id); ok {
+			return item, li, true
+		}
+	}
+
+	cr.missing(id)
+	return Item{}, Unknown, false
+}
+
+// trimID removes brackets around id.
+func (cr ContainerRequest) trimID(id string) string {
 	if len(id) > 2 &&
 		cr.c.cfg.bracketSymbol != "" &&
 		strings.HasPrefix(id, cr.c.cfg.bracketSymbol) &&
 		strings.HasSuffix(id, cr.c.cfg.bracketSymbol) {
 		id = id[1 : len(id)-1]
 	}
+	return id
+}
 
-	rsi, ok := cr.c.translations[key{lang: cr.lang, custom: ""}]
-	if ok {
-		var idx int
-		if idx, ok = rsi.index[id]; ok {
-			return rsi.items[idx], true
+// lookup looks up id in the language li only.
+func (cr ContainerRequest) lookup(snap *snapshot, li Index, id string) (Item, bool) {
+	for _, k := range cr.sets(li) {
+		rsi, ok := snap.lookup(k)
+		if !ok {
+			continue
 		}
-	}
-
-	if cr.c.cfg.primaryLanguage == Unknown {
-		return Item{}, false
-	}
-
-	rsi, ok = cr.c.translations[key{lang: cr.c.cfg.primaryLanguage, custom: ""}]
-	if ok {
-		var idx int
-		if idx, ok = rsi.index[id]; ok {
+		if idx, ok := rsi.index[id]; ok {
 			return rsi.items[idx], true
 		}
 	}
 	return Item{}, false
 }
 
-func (c *ContainerRequest) ValueWithDefault(id string, notFoundValue string) string {
-	res, ok := c.item(id)
+// missing reports missing id to the missing key handler.
+func (cr ContainerRequest) missing(id string) {
+	if cr.c.cfg.missingKeyHandler != nil {
+		cr.c.cfg.missingKeyHandler(cr.lang, id)
+	}
+}
+
+// ValueWithDefault returns translation of id or notFoundValue if id is not found.
+func (cr ContainerRequest) ValueWithDefault(id string, notFoundValue string) string {
+	res, ok := cr.item(cr.c.snapshot(), id)
 	if !ok {
 		return notFoundValue
 	}
@@ -397,42 +1066,155 @@ func (c *ContainerRequest) ValueWithDefault(id string, notFoundValue string) str
 }
 
 // JSON returns translation in JSON format.
-func (cr *ContainerRequest) JSON() ([]byte, error) {
-	kv := make(map[string]ResponseItem)
-	set, ok := cr.c.translations[key{lang: cr.lang}]
-	if !ok {
-		if cr.c.cfg.primaryLanguage != Unknown {
-			set, ok = cr.c.translations[key{lang: cr.c.cfg.primaryLanguage}]
+// Missing items are taken from the languages of the fallback chain and from
+// the primary language if the request strategy is ReturnInPrimaryLanguage.
+// Plural forms are grouped under the key without category, all forms of a key
+// are taken from the same language.
+// JSON of the languages is precomputed by ReadRegisteredFiles, see Bundle.
+func (cr ContainerRequest) JSON() ([]byte, error) {
+	snap := cr.c.snapshot()
+	if b, ok := cr.bundle(snap); ok {
+		return append([]byte(nil), b.JSON...), nil
+	}
+	return cr.json(snap, KeyFilter{})
+}
+
+// KeyFilter selects keys exported by FilteredJSON. A key is selected if it
+// matches any of the conditions, the empty filter selects all keys.
+// Keys are relative to the namespace of the request, plural forms are
+// selected by the key without category.
+type KeyFilter struct {
+	// Prefixes selects keys starting with any of the prefixes.
+	Prefixes []string
+	// Namespaces selects keys of sections and their nested sections:
+	// "orders" selects orders.Save and orders.list.Empty.
+	Namespaces []string
+	// Keys selects listed keys.
+	Keys []string
+}
+
+// match returns true if the filter selects key k.
+func (f KeyFilter) match(k string) bool {
+	if len(f.Prefixes) == 0 && len(f.Namespaces) == 0 && len(f.Keys) == 0 {
+		return true
+	}
+	for _, p := range f.Prefixes {
+		if strings.HasPrefix(k, p) {
+			return true
 		}
 	}
-	if !ok {
-		return nil, errors.New("no translation found")
+	for _, ns := range f.Namespaces {
+		if strings.HasPrefix(k, ns+NamespaceSeparator) {
+			return true
+		}
 	}
-
-	for _, item := range set.items {
-		kv[cr.c.genKey(item.Key)] = ResponseItem{
-			Value: item.Value,
-			Hint:  item.Hint,
+	for _, x := range f.Keys {
+		if k == x {
+			return true
 		}
 	}
+	return false
+}
+
+// FilteredJSON returns translation of keys selected by f in JSON format.
+// Missing items are filled in and keys are wrapped the same way JSON does.
+func (cr ContainerRequest) FilteredJSON(f KeyFilter) ([]byte, error) {
+	return cr.json(cr.c.snapshot(), f)
+}
+
+// json returns JSON of keys of the snapshot selected by f.
+func (cr ContainerRequest) json(snap *snapshot, f KeyFilter) ([]byte, error) {
+	kv, err := cr.responseMap(snap, f)
+	if err != nil {
+		return nil, err
+	}
+	return cr.c.marshalItems(kv)
+}
+
+// responseMap returns response items of the request selected by f, keys are
+// given without the namespace prefix and bracket symbols.
+func (cr ContainerRequest) responseMap(snap *snapshot, f KeyFilter) (map[string]ResponseItem, error) {
+	kv := make(map[string]ResponseItem)
 
-	if cr.c.cfg.primaryLanguage != Unknown && cr.c.cfg.primaryLanguage != cr.lang {
-		if set, ok = cr.c.translations[key{lang: cr.c.cfg.primaryLanguage}]; ok {
-			for _, item := range set.items {
-				k := cr.c.genKey(item.Key)
+	found := false
+	for _, li := range cr.chain() {
+		for _, sk := range cr.sets(li) {
+			set, ok := snap.lookup(sk)
+			if !ok {
+				continue
+			}
+			found = true
+
+			for k, ri := range responseItems(set, cr.ns, f) {
 				if _, ok := kv[k]; ok {
 					continue
 				}
-				kv[k] = ResponseItem{
-					Value: item.Value,
-					Hint:  item.Hint,
-				}
+				kv[k] = ri
 			}
 		}
 	}
 
-	buf, err := json.Marshal(kv)
-	return buf, err
+	if !found {
+		return nil, errors.New("no translation found")
+	}
+	return kv, nil
+}
+
+// marshalItems returns JSON of response items with keys generated by genKey.
+func (c *Container) marshalItems(kv map[string]ResponseItem) ([]byte, error) {
+	if c.cfg.bracketSymbol != "" {
+		res := make(map[string]ResponseItem, len(kv))
+		for k, ri := range kv {
+			res[c.genKey(k)] = ri
+		}
+		kv = res
+	}
+	return json.Marshal(kv)
+}
+
+// responseItems converts items of the set having keys starting with prefix
+// and selected by f into response items, the prefix is removed from keys.
+func responseItems(set Set, prefix string, f KeyFilter) map[string]ResponseItem {
+	res := make(map[string]ResponseItem, len(set.items))
+
+	for _, item := range set.items {
+		if !strings.HasPrefix(item.Key, prefix) {
+			continue
+		}
+
+		id, pc, ordinal, ok := splitPluralKey(item.Key)
+		if !f.match(strings.TrimPrefix(id, prefix)) {
+			continue
+		}
+		if !ok {
+			k := item.Key[len(prefix):]
+			ri := res[k]
+			ri.Value = item.Value
+			ri.Hint = item.Hint
+			res[k] = ri
+			continue
+		}
+
+		k := id[len(prefix):]
+		ri := res[k]
+		forms := &ri.Plural
+		if ordinal {
+			forms = &ri.Ordinal
+		}
+		if *forms == nil {
+			*forms = make(map[PluralCategory]string)
+		}
+		(*forms)[pc] = item.Value
+
+		// Value holds the form "other" if the key without category is not declared,
+		// cardinal forms take precedence over ordinal ones.
+		if _, ok := set.index[id]; !ok && pc == PluralOther && !(ordinal && ri.Plural != nil) {
+			ri.Value = item.Value
+			ri.Hint = item.Hint
+		}
+		res[k] = ri
+	}
+	return res
 }
 
 // genKey generates resource key for JSON response.
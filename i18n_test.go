@@ -1,7 +1,14 @@
 package language
 
 import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"testing/fstest"
 )
 
 func TestContainer(t *testing.T) {
@@ -37,8 +44,8 @@ func TestContainer(t *testing.T) {
 			t.Fatal(err)
 		}
 
-		if len(c.translations) != 2 {
-			t.Fatalf("expected %d translations, got %d", 2, len(c.translations))
+		if n := len(c.snapshot().sets); n != 2 {
+			t.Fatalf("expected %d translations, got %d", 2, n)
 		}
 	})
 
@@ -97,6 +104,600 @@ func TestContainer(t *testing.T) {
 	})
 }
 
+func TestRequestStrategy(t *testing.T) {
+	en, de := ToIndex("en"), ToIndex("de")
+
+	c := New(WithPrimaryLanguage(en), WithRequestStrategy(ReturnEmptyString))
+	if err := c.AddFiles(filepath.Join("testdata", "en.i18n"), filepath.Join("testdata", "de.i18n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		strategy RequestStrategy
+		exp      string
+	}{
+		{ReturnNotFoundVariable, "Exit" + NotFoundMarker},
+		{ReturnEmptyString, ""},
+		{ReturnInPrimaryLanguage, "Sign out"},
+	}
+
+	for _, tc := range cases {
+		cr := c.Lang(de).WithStrategy(tc.strategy)
+		if v := cr.Value("Exit"); v != tc.exp {
+			t.Errorf("strategy %d: expected '%s', got '%s'", tc.strategy, tc.exp, v)
+		}
+		if v := cr.Value("Missing"); tc.strategy != ReturnEmptyString && v != "Missing"+NotFoundMarker {
+			t.Errorf("strategy %d: expected 'Missing%s', got '%s'", tc.strategy, NotFoundMarker, v)
+		}
+	}
+
+	cr := c.Lang(de)
+	if v := cr.Value("Exit"); v != "" {
+		t.Errorf("container strategy: expected empty string, got '%s'", v)
+	}
+	if v := cr.ValueWithDefault("Exit", "-"); v != "-" {
+		t.Errorf("expected '-', got '%s'", v)
+	}
+
+	buf, err := cr.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kv map[string]ResponseItem
+	if err := json.Unmarshal(buf, &kv); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := kv["Exit"]; ok {
+		t.Errorf("unexpected primary language item in %s", buf)
+	}
+}
+
+func TestFallbackChain(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.i18n":      {Data: []byte("Save=Save\nCancel=Cancel\nExit=Exit\nHelp=Help // en hint\n")},
+		"de.i18n":      {Data: []byte("Save=Speichern\nCancel=Abbrechen // de hint\n")},
+		"de-AT.i18n":   {Data: []byte("Save=Sichern\n")},
+		"hr.i18n":      {Data: []byte("Exit=Izlaz\n")},
+		"sr.i18n":      {Data: []byte("Cancel=Откажи\n")},
+		"sr-Latn.i18n": {Data: []byte("Save=Sačuvaj\n")},
+	}
+
+	en, sr, srLatn := ToIndex("en"), ToIndex("sr"), ToIndex("sr-Latn")
+	c := New(
+		WithPrimaryLanguage(en),
+		WithFallback(srLatn, sr, ToIndex("hr")),
+	)
+	if err := c.AddFileByMaskFS(fsys, ".", "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		lang string
+		id   string
+		exp  string
+		hint string
+	}{
+		{"de-AT", "Save", "Sichern", ""},
+		{"de-AT", "Cancel", "Abbrechen", "de hint"},
+		{"de-AT", "Help", "Help", "en hint"},
+		{"sr-Latn", "Save", "Sačuvaj", ""},
+		{"sr-Latn", "Cancel", "Откажи", ""},
+		{"sr-Latn", "Exit", "Izlaz", ""},
+		{"sr-Latn", "Help", "Help", "en hint"},
+		{"sr-Latn-RS", "Exit", "Izlaz", ""},
+		{"sr", "Exit", "Exit", ""},
+	}
+
+	for _, tc := range cases {
+		cr := c.Lang(ToIndex(tc.lang))
+		if v := cr.Value(tc.id); v != tc.exp {
+			t.Errorf("%s.%s: expected '%s', got '%s'", tc.lang, tc.id, tc.exp, v)
+		}
+		if v := cr.Hint(tc.id); v != tc.hint {
+			t.Errorf("%s.%s: expected hint '%s', got '%s'", tc.lang, tc.id, tc.hint, v)
+		}
+	}
+
+	cr := c.Lang(ToIndex("sr-Latn-RS"))
+	buf, err := cr.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kv map[string]ResponseItem
+	if err := json.Unmarshal(buf, &kv); err != nil {
+		t.Fatal(err)
+	}
+	exp := map[string]string{"Save": "Sačuvaj", "Cancel": "Откажи", "Exit": "Izlaz", "Help": "Help"}
+	if len(kv) != len(exp) {
+		t.Fatalf("expected %d items, got %s", len(exp), buf)
+	}
+	for k, v := range exp {
+		if kv[k].Value != v {
+			t.Errorf("%s: expected '%s', got '%s'", k, v, kv[k].Value)
+		}
+	}
+
+	// other strategies follow the chain but not the primary language
+	buf, err = cr.WithStrategy(ReturnNotFoundVariable).JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	kv = nil
+	if err := json.Unmarshal(buf, &kv); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := kv["Help"]; ok || len(kv) != 3 {
+		t.Errorf("expected keys of the chain without primary language, got %s", buf)
+	}
+
+	de := c.Lang(ToIndex("de-AT")).WithStrategy(ReturnEmptyString)
+	if v := de.Value("Save"); v != "Sichern" {
+		t.Errorf("expected 'Sichern', got '%s'", v)
+	}
+	if v := de.Value("Cancel"); v != "Abbrechen" {
+		t.Errorf("expected 'Abbrechen', got '%s'", v)
+	}
+	if v := de.Value("Help"); v != "" {
+		t.Errorf("expected empty string, got '%s'", v)
+	}
+	if v := de.WithStrategy(ReturnNotFoundVariable).Value("Help"); v != "Help"+NotFoundMarker {
+		t.Errorf("expected not found variable, got '%s'", v)
+	}
+}
+
+func TestContainerFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"i18n/en.i18n":     {Data: []byte("Save=Save\nCancel=Cancel\nExit=Exit\n")},
+		"i18n/en.prj.i18n": {Data: []byte("Cancel=Close\n")},
+		"i18n/de.i18n":     {Data: []byte("Save=Speichern\n")},
+		"i18n/readme.txt":  {Data: []byte("not a translation")},
+	}
+
+	t.Run("AddFileByMaskFS", func(t *testing.T) {
+		c := New(WithSuffixes("prj"))
+		if err := c.AddFileByMaskFS(fsys, "i18n", "*.i18n"); err != nil {
+			t.Fatal(err)
+		}
+		if len(c.files) != 3 {
+			t.Fatalf("expected %d files, got %d", 3, len(c.files))
+		}
+		if err := c.ReadRegisteredFiles(); err != nil {
+			t.Fatal(err)
+		}
+
+		cr := c.Lang(ToIndex("en"))
+		if v := cr.Value("Cancel"); v != "Close" {
+			t.Fatalf("expected 'Close', got '%s'", v)
+		}
+		cr = c.Lang(ToIndex("de"))
+		if v := cr.Value("Save"); v != "Speichern" {
+			t.Fatalf("expected 'Speichern', got '%s'", v)
+		}
+	})
+
+	t.Run("AddFilesFSNotFound", func(t *testing.T) {
+		c := New()
+		if err := c.AddFilesFS(fsys, "i18n/ru.i18n"); err == nil {
+			t.Fatal("expected error")
+		}
+	})
+
+	t.Run("OverrideEmbeddedByDisk", func(t *testing.T) {
+		dir := t.TempDir()
+		fname := filepath.Join(dir, "en.i18n")
+		if err := os.WriteFile(fname, []byte("Exit=Sign out\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		c := New()
+		if err := c.AddFilesFS(fsys, "i18n/en.i18n"); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.AddFiles(fname); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.ReadRegisteredFiles(); err != nil {
+			t.Fatal(err)
+		}
+
+		cr := c.Lang(ToIndex("en"))
+		if v := cr.Value("Exit"); v != "Sign out" {
+			t.Fatalf("expected 'Sign out', got '%s'", v)
+		}
+		if v := cr.Value("Save"); v != "Save" {
+			t.Fatalf("expected 'Save', got '%s'", v)
+		}
+	})
+}
+
+func TestCustomDir(t *testing.T) {
+	writeDir := func(files map[string]string) string {
+		dir := t.TempDir()
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return dir
+	}
+
+	first := writeDir(map[string]string{
+		"en.i18n":     "Save=Keep\nCancel=Undo\n",
+		"en.prj.i18n": "Cancel=Revert\n",
+		"ru.i18n":     "Save=Сохранить\n",
+		"notes.txt":   "Save=Ignored\n",
+	})
+	second := writeDir(map[string]string{
+		"en.i18n": "Delete=Drop\n",
+	})
+
+	c := New(WithSuffixes("reports", "prj", "reports.prj"))
+	if err := c.AddFileByMask("testdata", "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddCustomDir(first, second); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AddCustomDir(filepath.Join("testdata", "en.i18n")); err == nil {
+		t.Fatal("expected error for a file")
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		lang string
+		id   string
+		exp  string
+	}{
+		{"en", "Save", "Keep"},
+		{"en", "Cancel", "Revert"},
+		{"en", "Delete", "Drop"},
+		{"en", "Exit", "Sign out"},
+		{"ru", "Save", "Сохранить"},
+		{"de", "Save", "Speichern"},
+	}
+
+	for _, tc := range cases {
+		cr := c.Lang(ToIndex(tc.lang))
+		if v := cr.Value(tc.id); v != tc.exp {
+			t.Errorf("%s.%s: expected '%s', got '%s'", tc.lang, tc.id, tc.exp, v)
+		}
+	}
+}
+
+func TestConcurrentReload(t *testing.T) {
+	dir := t.TempDir()
+	write := func(version int) {
+		files := map[string]string{
+			"en.i18n": fmt.Sprintf("Save=Save%d // hint%d\nExit=Exit%d\n", version, version, version),
+			"de.i18n": fmt.Sprintf("Save=Speichern%d // hint%d\n", version, version),
+		}
+		for name, content := range files {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	write(0)
+
+	c := New(WithPrimaryLanguage(ToIndex("en")))
+	if err := c.AddFileByMask(dir, "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	valid := map[string]bool{}
+	for v := 0; v < 2; v++ {
+		valid[fmt.Sprintf("Speichern%d", v)] = true
+		valid[fmt.Sprintf("hint%d", v)] = true
+		valid[fmt.Sprintf("Exit%d", v)] = true
+	}
+
+	done := make(chan struct{})
+	errs := make(chan error, 4)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cr := c.Lang(ToIndex("de"))
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+
+				if v := cr.Value("Save"); !valid[v] {
+					errs <- fmt.Errorf("unexpected value '%s'", v)
+					return
+				}
+				if v := cr.Hint("Save"); !valid[v] {
+					errs <- fmt.Errorf("unexpected hint '%s'", v)
+					return
+				}
+				if v := cr.Value("Exit"); !valid[v] {
+					errs <- fmt.Errorf("unexpected fallback value '%s'", v)
+					return
+				}
+
+				buf, err := cr.JSON()
+				if err != nil {
+					errs <- err
+					return
+				}
+				var kv map[string]ResponseItem
+				if err := json.Unmarshal(buf, &kv); err != nil {
+					errs <- err
+					return
+				}
+				if !valid[kv["Save"].Value] || !valid[kv["Exit"].Value] {
+					errs <- fmt.Errorf("unexpected JSON %s", buf)
+					return
+				}
+			}
+		}()
+	}
+
+	for i := 1; i <= 50; i++ {
+		write(i % 2)
+		if err := c.ReadRegisteredFiles(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	close(done)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestSections(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.i18n": {Data: []byte(strings.Join([]string{
+			"Save=Save",
+			"[orders]",
+			"Save=Save order // orders page",
+			"Items[one]=# item",
+			"Items[other]=# items",
+			"[orders.list]",
+			"Empty=No orders",
+			"[]",
+			"Exit=Exit",
+		}, "\n"))},
+		"de.i18n": {Data: []byte("[orders]\nSave=Bestellung speichern\n")},
+	}
+
+	c := New(WithPrimaryLanguage(ToIndex("en")), WithStrictMode())
+	if err := c.AddFileByMaskFS(fsys, ".", "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	de := c.Lang(ToIndex("de"))
+	cases := []struct {
+		got string
+		exp string
+	}{
+		{de.Value("Save"), "Save"},
+		{de.Value("orders.Save"), "Bestellung speichern"},
+		{de.Value("Exit"), "Exit"},
+		{de.Namespace("orders").Value("Save"), "Bestellung speichern"},
+		{de.Namespace("orders").Value("Exit"), "Exit" + NotFoundMarker},
+		{de.Namespace("orders").Namespace("list").Value("Empty"), "No orders"},
+		{de.Namespace("orders").Plural("Items", 3), "3 items"},
+		{c.Lang(ToIndex("en")).Namespace("orders").Hint("Save"), "orders page"},
+	}
+	for i, tc := range cases {
+		if tc.got != tc.exp {
+			t.Errorf("%d: expected %q, got %q", i, tc.exp, tc.got)
+		}
+	}
+
+	buf, err := de.Namespace("orders").JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kv map[string]ResponseItem
+	if err := json.Unmarshal(buf, &kv); err != nil {
+		t.Fatal(err)
+	}
+	if len(kv) != 3 || kv["Save"].Value != "Bestellung speichern" || kv["list.Empty"].Value != "No orders" || kv["Items"].Plural[PluralOne] != "# item" {
+		t.Errorf("unexpected JSON %s", buf)
+	}
+
+	bad := fstest.MapFS{"en.i18n": {Data: []byte("[my section]\nSave=Save\n")}}
+	c = New(WithStrictMode())
+	if err := c.AddFilesFS(bad, "en.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err == nil || err.Error() != `en.i18n:1:1: invalid section name "my section"` {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestFilteredJSON(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.i18n": {Data: []byte(strings.Join([]string{
+			"Save=Save",
+			"Exit=Exit",
+			"[orders]",
+			"Save=Save order",
+			"Items[one]=# item",
+			"Items[other]=# items",
+			"[orders.list]",
+			"Empty=No orders",
+			"[reports]",
+			"Title=Reports",
+			"[ordersArchive]",
+			"Title=Archive",
+		}, "\n"))},
+		"de.i18n": {Data: []byte("[orders]\nSave=Bestellung speichern\n")},
+	}
+
+	c := New(WithPrimaryLanguage(ToIndex("en")), WithBrackets("#"))
+	if err := c.AddFileByMaskFS(fsys, ".", "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name string
+		cr   ContainerRequest
+		f    KeyFilter
+		exp  map[string]string
+	}{
+		{
+			name: "Namespace",
+			cr:   c.Lang(ToIndex("de")),
+			f:    KeyFilter{Namespaces: []string{"orders"}},
+			exp: map[string]string{
+				"#orders.Save#":       "Bestellung speichern",
+				"#orders.Items#":      "# items",
+				"#orders.list.Empty#": "No orders",
+			},
+		},
+		{
+			name: "Prefix",
+			cr:   c.Lang(ToIndex("de")),
+			f:    KeyFilter{Prefixes: []string{"orders"}},
+			exp: map[string]string{
+				"#orders.Save#":         "Bestellung speichern",
+				"#orders.Items#":        "# items",
+				"#orders.list.Empty#":   "No orders",
+				"#ordersArchive.Title#": "Archive",
+			},
+		},
+		{
+			name: "Keys",
+			cr:   c.Lang(ToIndex("de")),
+			f:    KeyFilter{Keys: []string{"Exit", "reports.Title", "Unknown"}, Namespaces: []string{"orders.list"}},
+			exp: map[string]string{
+				"#Exit#":              "Exit",
+				"#reports.Title#":     "Reports",
+				"#orders.list.Empty#": "No orders",
+			},
+		},
+		{
+			name: "RequestNamespace",
+			cr:   c.Lang(ToIndex("de")).Namespace("orders"),
+			f:    KeyFilter{Keys: []string{"Save", "Items"}},
+			exp: map[string]string{
+				"#Save#":  "Bestellung speichern",
+				"#Items#": "# items",
+			},
+		},
+		{
+			name: "NoFallback",
+			cr:   c.Lang(ToIndex("de")).WithStrategy(ReturnEmptyString),
+			f:    KeyFilter{Namespaces: []string{"orders"}},
+			exp: map[string]string{
+				"#orders.Save#": "Bestellung speichern",
+			},
+		},
+	}
+
+	for _, tc := range cases {
+		buf, err := tc.cr.FilteredJSON(tc.f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var kv map[string]ResponseItem
+		if err := json.Unmarshal(buf, &kv); err != nil {
+			t.Fatal(err)
+		}
+		got := make(map[string]string)
+		for k, ri := range kv {
+			got[k] = ri.Value
+		}
+		if fmt.Sprint(got) != fmt.Sprint(tc.exp) {
+			t.Errorf("%s: expected %v, got %v", tc.name, tc.exp, got)
+		}
+	}
+}
+
+func TestDomains(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.i18n":             {Data: []byte("Save=Save\nCancel=Cancel\nExit=Exit\n")},
+		"en.prj.i18n":         {Data: []byte("Exit=Sign out\n")},
+		"en.reports.i18n":     {Data: []byte("Save=Save report\nTitle=Reports\nPrint=Print\n")},
+		"en.reports.prj.i18n": {Data: []byte("Print=Print PDF\n")},
+		"de.i18n":             {Data: []byte("Save=Speichern\n")},
+		"de.reports.i18n":     {Data: []byte("Title=Berichte\n")},
+	}
+
+	c := New(WithPrimaryLanguage(ToIndex("en")), WithSuffixes("prj"), WithDomains("reports"))
+	if err := c.AddFileByMaskFS(fsys, ".", "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	en := c.Lang(ToIndex("en"))
+	de := c.Lang(ToIndex("de"))
+	cases := []struct {
+		got string
+		exp string
+	}{
+		{en.Value("Save"), "Save"},
+		{en.Value("Title"), "Title" + NotFoundMarker},
+		{en.Value("Exit"), "Sign out"},
+		{en.Domain("reports").Value("Save"), "Save report"},
+		{en.Domain("reports").Value("Print"), "Print PDF"},
+		{en.Domain("reports").Value("Exit"), "Sign out"},
+		{de.Domain("reports").Value("Title"), "Berichte"},
+		// the base set of the language goes before the domain of the primary language
+		{de.Domain("reports").Value("Save"), "Speichern"},
+		{de.Domain("reports").Value("Print"), "Print PDF"},
+		{de.Domain("unknown").Value("Save"), "Speichern"},
+	}
+	for i, tc := range cases {
+		if tc.got != tc.exp {
+			t.Errorf("%d: expected %q, got %q", i, tc.exp, tc.got)
+		}
+	}
+
+	buf, err := de.Domain("reports").JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kv map[string]ResponseItem
+	if err := json.Unmarshal(buf, &kv); err != nil {
+		t.Fatal(err)
+	}
+	if len(kv) != 5 || kv["Title"].Value != "Berichte" || kv["Save"].Value != "Speichern" || kv["Print"].Value != "Print PDF" {
+		t.Errorf("unexpected JSON %s", buf)
+	}
+
+	// without domains suffixes override the base files
+	c = New(WithSuffixes("reports"))
+	if err := c.AddFileByMaskFS(fsys, ".", "en.*"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+	if v := c.Lang(ToIndex("en")).Value("Save"); v != "Save report" {
+		t.Errorf("expected %q, got %q", "Save report", v)
+	}
+}
+
 /*
 func TestNewBitSet(t *testing.T) {
 
@@ -0,0 +1,119 @@
+package language
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+)
+
+// IncludeDirective starts a line including another translation file:
+// @include common/buttons.i18n. The path is relative to the including file.
+var IncludeDirective = "@include"
+
+// loadState holds files being loaded by a single ReadRegisteredFiles call.
+type loadState struct {
+	// stack holds names of files including the file being loaded.
+	stack []string
+	// included collects all included files.
+	included []file
+	// prefix is the section prefix of the include directive being loaded.
+	prefix string
+	// namespaces collects names of all sections and their parents.
+	namespaces map[string]bool
+}
+
+// addNamespace records the section name and its parent sections.
+func (ls *loadState) addNamespace(name string) {
+	if ls.namespaces == nil {
+		ls.namespaces = make(map[string]bool)
+	}
+	for name != "" && !ls.namespaces[name] {
+		ls.namespaces[name] = true
+		i := strings.LastIndex(name, NamespaceSeparator)
+		if i == -1 {
+			break
+		}
+		name = name[:i]
+	}
+}
+
+// includePath returns included file name if line is an include directive.
+func includePath(line string) (string, bool) {
+	if !strings.HasPrefix(line, IncludeDirective) {
+		return "", false
+	}
+	rest := line[len(IncludeDirective):]
+	if rest != "" && rest[0] != ' ' && rest[0] != '\t' {
+		return "", false
+	}
+	return strings.Trim(strings.TrimSpace(rest), `"`), true
+}
+
+// include returns the file name refers to relative to f.
+// Files included from fs.FS are read from the same fs.FS.
+func (f *file) include(name string) (file, error) {
+	res := file{key: f.key, fsys: f.fsys}
+
+	if f.fsys == nil {
+		if !filepath.IsAbs(name) {
+			name = filepath.Join(filepath.Dir(f.fullName), name)
+		}
+		res.fullName = filepath.Clean(name)
+		res.name = filepath.Base(res.fullName)
+		return res, nil
+	}
+
+	res.fullName = path.Join(path.Dir(f.fullName), name)
+	if !fs.ValidPath(res.fullName) {
+		return res, fmt.Errorf("invalid include path %q", name)
+	}
+	res.name = path.Base(res.fullName)
+	return res, nil
+}
+
+// cycle returns the include chain if name is being loaded, empty string otherwise.
+func (ls *loadState) cycle(name string) string {
+	for i, s := range ls.stack {
+		if s == name {
+			return strings.Join(append(ls.stack[i:len(ls.stack):len(ls.stack)], name), " -> ")
+		}
+	}
+	return ""
+}
+
+// includes returns true if f is included by any loaded file.
+func (ls *loadState) includes(f *file) bool {
+	name := f.fullName
+	if f.fsys == nil {
+		name = filepath.Clean(name)
+	}
+	for i := range ls.included {
+		if ls.included[i].fullName == name && sameFS(ls.included[i].fsys, f.fsys) {
+			return true
+		}
+	}
+	return false
+}
+
+// sameFS returns true if a and b are the same file system. File systems
+// of not comparable types like fstest.MapFS are compared by reference.
+func sameFS(a, b fs.FS) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if va.Type() != vb.Type() {
+		return false
+	}
+	switch va.Kind() {
+	case reflect.Map, reflect.Ptr, reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		return va.Pointer() == vb.Pointer()
+	}
+	if va.Type().Comparable() {
+		return a == b
+	}
+	return false
+}
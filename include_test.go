@@ -0,0 +1,192 @@
+package language
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestInclude(t *testing.T) {
+	fsys := fstest.MapFS{
+		"i18n/en.i18n": {Data: []byte(strings.Join([]string{
+			"@include shared/buttons.i18n",
+			"Save=Save changes",
+			"[orders]",
+			"@include shared/buttons.i18n",
+		}, "\n"))},
+		"i18n/shared/buttons.i18n": {Data: []byte("Save=Save\nCancel=Cancel\n@include ../../common/messages.i18n\n")},
+		"common/messages.i18n":     {Data: []byte("Required=This field is required\n")},
+	}
+
+	c := New(WithPrimaryLanguage(ToIndex("en")))
+	if err := c.AddFilesFS(fsys, "i18n/en.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	cr := c.Lang(ToIndex("en"))
+	cases := []struct {
+		key string
+		exp string
+	}{
+		{"Save", "Save changes"},
+		{"Cancel", "Cancel"},
+		{"Required", "This field is required"},
+		{"orders.Save", "Save"},
+		{"orders.Required", "This field is required"},
+	}
+	for _, tc := range cases {
+		if v := cr.Value(tc.key); v != tc.exp {
+			t.Errorf("%s: expected %q, got %q", tc.key, tc.exp, v)
+		}
+	}
+
+	t.Run("Cycle", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"en.i18n":  {Data: []byte("Save=Save\n@include a.i18n\n")},
+			"a.i18n":   {Data: []byte("@include b/b.i18n\n")},
+			"b/b.i18n": {Data: []byte("@include ../a.i18n\n")},
+			"de.i18n":  {Data: []byte("@include missing.i18n\n")},
+			"nl.i18n":  {Data: []byte("@include\n")},
+		}
+
+		c := New()
+		if err := c.AddFilesFS(fsys, "en.i18n"); err != nil {
+			t.Fatal(err)
+		}
+		err := c.ReadRegisteredFiles()
+		if err == nil || !strings.Contains(err.Error(), "include cycle: a.i18n -> b/b.i18n -> a.i18n") {
+			t.Errorf("expected cycle error, got %v", err)
+		}
+
+		c = New(WithStrictMode())
+		if err := c.AddFilesFS(fsys, "en.i18n", "de.i18n", "nl.i18n"); err != nil {
+			t.Fatal(err)
+		}
+		err = c.ReadRegisteredFiles()
+		pe, ok := err.(ParseErrors)
+		if !ok || len(pe) != 3 {
+			t.Fatalf("expected 3 diagnostics, got %v", err)
+		}
+		got := make(map[string]string)
+		for _, d := range pe {
+			got[d.File] = d.Error()
+		}
+		exp := map[string]string{
+			"b/b.i18n": "b/b.i18n:1:1: include cycle: a.i18n -> b/b.i18n -> a.i18n",
+			"de.i18n":  "de.i18n:1:1: include missing.i18n: open missing.i18n: file does not exist",
+			"nl.i18n":  "nl.i18n:1:1: include path expected",
+		}
+		for f, e := range exp {
+			if got[f] != e {
+				t.Errorf("expected %q, got %q", e, got[f])
+			}
+		}
+	})
+
+	t.Run("SameDir", func(t *testing.T) {
+		fsys := fstest.MapFS{
+			"i18n/en.i18n":     {Data: []byte("@include common.i18n\nSave=Save changes\n")},
+			"i18n/de.i18n":     {Data: []byte("@include common.i18n\nSave=Speichern\n")},
+			"i18n/common.i18n": {Data: []byte("Brand=Acme\n")},
+		}
+
+		c := New(WithPrimaryLanguage(ToIndex("en")))
+		if err := c.AddFileByMaskFS(fsys, "i18n", "*.i18n"); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.ReadRegisteredFiles(); err != nil {
+			t.Fatal(err)
+		}
+
+		langs := c.Languages()
+		if len(langs) != 2 {
+			t.Fatalf("expected 2 languages, got %v", langs)
+		}
+		for _, li := range langs {
+			if code := IndexToCode(li); code != "en" && code != "de" {
+				t.Errorf("unexpected language %q", code)
+			}
+		}
+		if v := c.Lang(ToIndex("de")).Value("Brand"); v != "Acme" {
+			t.Errorf("expected %q, got %q", "Acme", v)
+		}
+
+		dir := t.TempDir()
+		for name, f := range fsys {
+			if err := os.WriteFile(filepath.Join(dir, filepath.Base(name)), f.Data, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		c = New(WithPrimaryLanguage(ToIndex("en")))
+		if err := c.AddCustomDir(dir); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.ReadRegisteredFiles(); err != nil {
+			t.Fatal(err)
+		}
+		if n := len(c.Languages()); n != 2 {
+			t.Errorf("expected 2 languages from the custom directory, got %v", c.Languages())
+		}
+		if v := c.Lang(ToIndex("en")).Value("Brand"); v != "Acme" {
+			t.Errorf("expected %q, got %q", "Acme", v)
+		}
+	})
+
+	t.Run("Disk", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := os.MkdirAll(filepath.Join(dir, "shared"), 0755); err != nil {
+			t.Fatal(err)
+		}
+		shared := filepath.Join(dir, "shared", "buttons.i18n")
+		write := func(fname, content string, mt time.Time) {
+			if err := os.WriteFile(fname, []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Chtimes(fname, mt, mt); err != nil {
+				t.Fatal(err)
+			}
+		}
+		mt := time.Now()
+		write(filepath.Join(dir, "en.i18n"), "@include shared/buttons.i18n\nExit=Sign out\n", mt)
+		write(shared, "Cancel=Cancel\nExit=Exit\n", mt)
+
+		c := New(WithWatchInterval(5 * time.Millisecond))
+		if err := c.AddFiles(filepath.Join(dir, "en.i18n")); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.ReadRegisteredFiles(); err != nil {
+			t.Fatal(err)
+		}
+
+		cr := c.Lang(ToIndex("en"))
+		if v := cr.Value("Cancel"); v != "Cancel" {
+			t.Fatalf("expected %q, got %q", "Cancel", v)
+		}
+		if v := cr.Value("Exit"); v != "Sign out" {
+			t.Fatalf("expected %q, got %q", "Sign out", v)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if err := c.ListenFileChangeContext(ctx); err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+
+		write(shared, "Cancel=Abort\n", mt.Add(time.Second))
+		deadline := time.Now().Add(5 * time.Second)
+		for cr.Value("Cancel") != "Abort" {
+			if time.Now().After(deadline) {
+				t.Fatalf("expected %q, got %q", "Abort", cr.Value("Cancel"))
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"fmt"
+	"strings"
 	"sync"
 )
 
@@ -27,7 +28,9 @@ func SetNoLanguage(lang string) Index {
 }
 
 // Parse returns index of language code, if not found returns -1.
+// The code is canonicalized by CanonicalTag before look up.
 func Parse(lang string) Index {
+	lang = CanonicalTag(lang)
 	res := Unknown
 	mux.RLock()
 	for i := range languages {
@@ -41,7 +44,10 @@ func Parse(lang string) Index {
 }
 
 // ToIndex returns index by language code: ru, en, sr, cz, created new if not found.
+// The code is a BCP 47 language tag canonicalized by CanonicalTag, thus
+// "en_US", "EN-us" and "en-US" have the same index.
 func ToIndex(lang string) Index {
+	lang = CanonicalTag(lang)
 	mux.RLock()
 	res := toIndex(lang, false)
 	if res != Unknown {
@@ -69,10 +75,99 @@ func toIndex(lang string, addUnknown bool) Index {
 	return Index(len(languages) - 1)
 }
 
+// CanonicalTag returns canonical form of BCP 47 language tag: "-" as
+// a separator, lowercase language, titlecase script and uppercase region.
+// For example "EN_us" becomes "en-US", "sr_latn_rs" becomes "sr-Latn-RS".
+// Variants and extensions are lowercased.
+func CanonicalTag(tag string) string {
+	subtags := strings.FieldsFunc(strings.TrimSpace(tag), func(r rune) bool {
+		return r == '-' || r == '_'
+	})
+
+	extension := false
+	for i, st := range subtags {
+		st = strings.ToLower(st)
+		switch {
+		case i == 0 || extension:
+		case len(st) == 1:
+			// singleton starts extension or private use subtags.
+			extension = true
+		case len(st) == 4 && isAlpha(st) && i == 1:
+			// script
+			st = strings.ToUpper(st[:1]) + st[1:]
+		case (len(st) == 2 && isAlpha(st)) || (len(st) == 3 && isDigit(st)):
+			// region
+			st = strings.ToUpper(st)
+		}
+		subtags[i] = st
+	}
+	return strings.Join(subtags, "-")
+}
+
+// parentTag returns the tag without the last subtag, empty string
+// for a tag consisting of a language only.
+func parentTag(tag string) string {
+	i := strings.LastIndex(tag, "-")
+	if i == -1 {
+		return ""
+	}
+	tag = tag[:i]
+
+	// drop dangling singleton: "en-x-foo" -> "en-x" -> "en"
+	if j := strings.LastIndex(tag, "-"); j != -1 && j == len(tag)-2 {
+		tag = tag[:j]
+	}
+	return tag
+}
+
+func isAlpha(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 'a' || s[i] > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigit(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Parent returns index of the closest registered parent language: "sr-Latn"
+// for "sr-Latn-RS", "sr" if "sr-Latn" is not registered. Returns Unknown for
+// a language without region, script or other subtags and if no parent is registered.
+func Parent(index Index) Index {
+	mux.RLock()
+	defer mux.RUnlock()
+	if index < 0 || int(index) >= len(languages) {
+		return Unknown
+	}
+	for tag := parentTag(languages[index]); tag != ""; tag = parentTag(tag) {
+		if li := toIndex(tag, false); li != Unknown {
+			return li
+		}
+	}
+	return Unknown
+}
+
+// Base returns index of the registered language without subtags closest to
+// it: "sr" for "sr-Latn-RS".
+func Base(index Index) Index {
+	for p := Parent(index); p != Unknown; p = Parent(index) {
+		index = p
+	}
+	return index
+}
+
 // IndexToCode returns language code.
 func IndexToCode(index Index) string {
 	mux.RLock()
-	if int(index) >= len(languages) {
+	if index < 0 || int(index) >= len(languages) {
 		mux.RUnlock()
 		return UnknownLanguageCode
 	}
@@ -86,8 +181,6 @@ func Supported() []string {
 	return languages
 }
 
-// TODO сделать определение ближайшего языка на основании заголовка Accepted-Language
-
 // NameColumn is a type of column Name in regular reference table
 type NameColumn []byte
 
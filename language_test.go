@@ -0,0 +1,113 @@
+package language
+
+import (
+	"testing"
+)
+
+func TestCanonicalTag(t *testing.T) {
+	cases := []struct {
+		tag string
+		exp string
+	}{
+		{"en", "en"},
+		{"EN", "en"},
+		{"en_us", "en-US"},
+		{"en-US", "en-US"},
+		{"sr_latn", "sr-Latn"},
+		{"SR-CYRL-rs", "sr-Cyrl-RS"},
+		{"es-419", "es-419"},
+		{"de-CH-1996", "de-CH-1996"},
+		{"en-US-x-Twain", "en-US-x-twain"},
+		{" pt_br ", "pt-BR"},
+	}
+
+	for _, tc := range cases {
+		if s := CanonicalTag(tc.tag); s != tc.exp {
+			t.Errorf("%q: expected %q, got %q", tc.tag, tc.exp, s)
+		}
+	}
+}
+
+func TestToIndexCanonical(t *testing.T) {
+	en := ToIndex("en-US")
+	for _, code := range []string{"en_US", "EN-us", "en-us"} {
+		if li := ToIndex(code); li != en {
+			t.Errorf("%s: expected %d, got %d", code, en, li)
+		}
+		if li := Parse(code); li != en {
+			t.Errorf("%s: expected %d, got %d", code, en, li)
+		}
+	}
+	if code := IndexToCode(en); code != "en-US" {
+		t.Errorf("expected 'en-US', got '%s'", code)
+	}
+	if code := IndexToCode(Unknown); code != UnknownLanguageCode {
+		t.Errorf("expected '%s', got '%s'", UnknownLanguageCode, code)
+	}
+
+	sr, srLatn := ToIndex("sr"), ToIndex("sr-Latn")
+	latn, cyrl := ToIndex("sr_latn_RS"), ToIndex("sr-Cyrl")
+	if latn == cyrl {
+		t.Fatal("expected different indexes")
+	}
+	if p := Parent(latn); IndexToCode(p) != "sr-Latn" {
+		t.Errorf("expected parent 'sr-Latn', got '%s'", IndexToCode(p))
+	}
+	if p := Parent(Parent(latn)); p != Parent(cyrl) || IndexToCode(p) != "sr" {
+		t.Errorf("expected common parent 'sr', got '%s'", IndexToCode(p))
+	}
+	if p := Parent(ToIndex("sr")); p != Unknown {
+		t.Errorf("expected no parent, got '%s'", IndexToCode(p))
+	}
+	if b := Base(latn); b != sr || Parent(latn) != srLatn {
+		t.Errorf("expected base 'sr', got '%s'", IndexToCode(b))
+	}
+
+	// parents are not registered by ToIndex
+	base := unusedTag(t)
+	li := ToIndex(base + "-Latn-RS")
+	if Parse(base) != Unknown || Parse(base+"-Latn") != Unknown {
+		t.Errorf("expected parents of '%s' not to be registered", IndexToCode(li))
+	}
+	if p := Parent(li); p != Unknown {
+		t.Errorf("expected no parent, got '%s'", IndexToCode(p))
+	}
+	if p := ToIndex(base); Parent(li) != p || Base(li) != p {
+		t.Errorf("expected parent '%s' once registered, got '%s'", base, IndexToCode(Parent(li)))
+	}
+}
+
+// unusedTag returns a private use language code without registered tags
+// starting with it, so tests are repeatable with the global registry.
+func unusedTag(t *testing.T) string {
+	for a := 'a'; a <= 't'; a++ {
+		for b := 'a'; b <= 'z'; b++ {
+			tag := string([]rune{'q', a, b})
+			if Parse(tag) == Unknown && Parse(tag+"-Latn") == Unknown && Parse(tag+"-Latn-RS") == Unknown {
+				return tag
+			}
+		}
+	}
+	t.Fatal("no unused language code")
+	return ""
+}
+
+func TestParseFileName(t *testing.T) {
+	cases := []struct {
+		name   string
+		lang   string
+		suffix string
+	}{
+		{"pt.i18n", "pt", ""},
+		{"pt-BR.i18n", "pt-BR", ""},
+		{"pt_br.reports.i18n", "pt-BR", "reports"},
+		{"sr-Latn.prj.i18n", "sr-Latn", "prj"},
+	}
+
+	for _, tc := range cases {
+		li, suffix := parseFileName(tc.name)
+		if li != ToIndex(tc.lang) || suffix != tc.suffix {
+			t.Errorf("%s: expected %s/%q, got %s/%q", tc.name, tc.lang, tc.suffix, IndexToCode(li), suffix)
+		}
+	}
+}
@@ -0,0 +1,597 @@
+package language
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// message is a compiled ICU MessageFormat pattern.
+type message []msgNode
+
+// msgNode is a part of a message.
+type msgNode interface {
+	render(r *msgRenderer)
+}
+
+// msgText is a literal text.
+type msgText string
+
+// msgPound is # inside plural case, replaced by the plural argument.
+type msgPound struct{}
+
+// msgArg is a simple argument: {name}, {name, number}, {name, date, short}.
+type msgArg struct {
+	name  string
+	typ   string
+	style string
+}
+
+// msgPlural is {name, plural, ...} or {name, selectordinal, ...} argument.
+type msgPlural struct {
+	name    string
+	ordinal bool
+	offset  float64
+	// exact holds cases like =0, =1.
+	exact map[float64]message
+	cases map[PluralCategory]message
+}
+
+// msgSelect is {name, select, ...} argument.
+type msgSelect struct {
+	name  string
+	cases map[string]message
+}
+
+// msgError describes a syntax error in a message.
+type msgError struct {
+	// Offset is the byte offset in the message.
+	Offset int
+	Msg    string
+}
+
+func (e *msgError) Error() string {
+	return fmt.Sprintf("offset %d: %s", e.Offset, e.Msg)
+}
+
+// compileMessage parses ICU MessageFormat pattern s.
+//
+// Supported arguments are {name}, {name, number[, style]}, {name, date[, style]},
+// {name, time[, style]}, {name, relative}, {name, plural, [offset:n] =n {...} category {...}},
+// {name, selectordinal, ...} and {name, select, key {...} other {...}}. Apostrophe quotes special
+// characters: '{' is a literal brace, two apostrophes are a literal apostrophe.
+func compileMessage(s string) (message, error) {
+	p := msgParser{s: s}
+	m, err := p.parseMessage(false, false)
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.s) {
+		return nil, p.errorf("unexpected '%c'", p.s[p.pos])
+	}
+	return m, nil
+}
+
+// isMessageArgument returns true if s is a single MessageFormat argument
+// like {count, plural, one {...} other {...}}.
+func isMessageArgument(s string) bool {
+	m, err := compileMessage(s)
+	if err != nil || len(m) != 1 {
+		return false
+	}
+	_, text := m[0].(msgText)
+	return !text
+}
+
+type msgParser struct {
+	s   string
+	pos int
+}
+
+func (p *msgParser) errorf(format string, a ...interface{}) error {
+	return &msgError{Offset: p.pos, Msg: fmt.Sprintf(format, a...)}
+}
+
+func (p *msgParser) skipSpace() {
+	for p.pos < len(p.s) && isMsgSpace(p.s[p.pos]) {
+		p.pos++
+	}
+}
+
+func isMsgSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// parseMessage parses text and arguments until the end of the pattern or
+// until '}' closing the case of plural or select if nested.
+func (p *msgParser) parseMessage(inPlural, nested bool) (message, error) {
+	var (
+		res  message
+		text strings.Builder
+	)
+
+	flush := func() {
+		if text.Len() > 0 {
+			res = append(res, msgText(text.String()))
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.s) {
+		ch := p.s[p.pos]
+		switch {
+		case ch == '\'':
+			p.parseQuoted(&text, inPlural)
+		case ch == '{':
+			flush()
+			node, err := p.parseArgument(inPlural)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, node)
+		case ch == '}':
+			if !nested {
+				return nil, p.errorf("unexpected '}'")
+			}
+			flush()
+			return res, nil
+		case ch == '#' && inPlural:
+			flush()
+			res = append(res, msgPound{})
+			p.pos++
+		default:
+			text.WriteByte(ch)
+			p.pos++
+		}
+	}
+
+	if nested {
+		return nil, p.errorf("unclosed case, '}' expected")
+	}
+	flush()
+	return res, nil
+}
+
+// parseQuoted handles apostrophe at the current position.
+func (p *msgParser) parseQuoted(text *strings.Builder, inPlural bool) {
+	p.pos++ // skip '
+	if p.pos >= len(p.s) {
+		text.WriteByte('\'')
+		return
+	}
+
+	next := p.s[p.pos]
+	if next == '\'' {
+		text.WriteByte('\'')
+		p.pos++
+		return
+	}
+
+	if next != '{' && next != '}' && !(next == '#' && inPlural) {
+		text.WriteByte('\'')
+		return
+	}
+
+	for p.pos < len(p.s) {
+		ch := p.s[p.pos]
+		p.pos++
+		if ch != '\'' {
+			text.WriteByte(ch)
+			continue
+		}
+		if p.pos < len(p.s) && p.s[p.pos] == '\'' {
+			text.WriteByte('\'')
+			p.pos++
+			continue
+		}
+		return
+	}
+}
+
+// parseWord reads an identifier: argument name, type or case key.
+func (p *msgParser) parseWord() string {
+	from := p.pos
+	for p.pos < len(p.s) {
+		ch := p.s[p.pos]
+		if isMsgSpace(ch) || ch == ',' || ch == '{' || ch == '}' {
+			break
+		}
+		p.pos++
+	}
+	return p.s[from:p.pos]
+}
+
+func (p *msgParser) expect(ch byte) error {
+	if p.pos >= len(p.s) {
+		return p.errorf("'%c' expected, end of message found", ch)
+	}
+	if p.s[p.pos] != ch {
+		return p.errorf("'%c' expected, '%c' found", ch, p.s[p.pos])
+	}
+	p.pos++
+	return nil
+}
+
+func (p *msgParser) parseArgument(inPlural bool) (msgNode, error) {
+	p.pos++ // skip {
+	p.skipSpace()
+
+	from := p.pos
+	name := p.parseWord()
+	if !isPlaceholderName(name) {
+		p.pos = from
+		return nil, p.errorf("invalid argument name %q", name)
+	}
+
+	p.skipSpace()
+	if p.pos < len(p.s) && p.s[p.pos] == '}' {
+		p.pos++
+		return msgArg{name: name}, nil
+	}
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+
+	p.skipSpace()
+	from = p.pos
+	typ := p.parseWord()
+	p.skipSpace()
+
+	switch typ {
+	case "plural", "selectordinal":
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		return p.parsePlural(name, typ == "selectordinal")
+	case "select":
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+		return p.parseSelect(name, inPlural)
+	}
+
+	if !isSimpleArgType(typ) {
+		p.pos = from
+		return nil, p.errorf("unknown argument type %q", typ)
+	}
+
+	arg := msgArg{name: name, typ: typ}
+	if p.pos < len(p.s) && p.s[p.pos] == ',' {
+		p.pos++
+		from = p.pos
+		for p.pos < len(p.s) && p.s[p.pos] != '}' {
+			if p.s[p.pos] == '{' {
+				return nil, p.errorf("unexpected '{' in argument style")
+			}
+			p.pos++
+		}
+		arg.style = strings.TrimSpace(p.s[from:p.pos])
+		if typ == "number" && !isNumberStyle(arg.style) {
+			p.pos = from
+			return nil, p.errorf("unsupported number style %q", arg.style)
+		}
+		if typ == "relative" && arg.style != "" {
+			p.pos = from
+			return nil, p.errorf("unsupported relative style %q", arg.style)
+		}
+	}
+
+	if err := p.expect('}'); err != nil {
+		return nil, err
+	}
+	return arg, nil
+}
+
+func isSimpleArgType(typ string) bool {
+	switch typ {
+	case "number", "date", "time", "relative":
+		return true
+	}
+	return false
+}
+
+// parseCases parses "key {message} key {message}" up to closing '}'.
+func (p *msgParser) parseCases(inPlural bool, fn func(key string, m message) error) error {
+	found := false
+	for {
+		p.skipSpace()
+		if p.pos >= len(p.s) {
+			return p.errorf("'}' expected, end of message found")
+		}
+		if p.s[p.pos] == '}' {
+			p.pos++
+			break
+		}
+
+		from := p.pos
+		key := p.parseWord()
+		if key == "" {
+			return p.errorf("case key expected")
+		}
+
+		p.skipSpace()
+		if err := p.expect('{'); err != nil {
+			return err
+		}
+
+		m, err := p.parseMessage(inPlural, true)
+		if err != nil {
+			return err
+		}
+		p.pos++ // skip }
+
+		end := p.pos
+		p.pos = from
+		if err := fn(key, m); err != nil {
+			return err
+		}
+		p.pos = end
+		found = true
+	}
+
+	if !found {
+		return p.errorf("no cases found")
+	}
+	return nil
+}
+
+func (p *msgParser) parsePlural(name string, ordinal bool) (msgNode, error) {
+	res := msgPlural{
+		name:    name,
+		ordinal: ordinal,
+		exact:   make(map[float64]message),
+		cases:   make(map[PluralCategory]message),
+	}
+
+	p.skipSpace()
+	if strings.HasPrefix(p.s[p.pos:], "offset:") {
+		p.pos += len("offset:")
+		p.skipSpace()
+		word := p.parseWord()
+		offset, err := strconv.ParseFloat(word, 64)
+		if err != nil {
+			return nil, p.errorf("invalid offset %q", word)
+		}
+		res.offset = offset
+	}
+
+	from := p.pos
+	err := p.parseCases(true, func(key string, m message) error {
+		if strings.HasPrefix(key, "=") {
+			x, err := strconv.ParseFloat(key[1:], 64)
+			if err != nil {
+				return p.errorf("invalid plural case %q", key)
+			}
+			res.exact[x] = m
+			return nil
+		}
+		pc := PluralCategory(key)
+		if !pc.IsValid() {
+			return p.errorf("invalid plural category %q", key)
+		}
+		res.cases[pc] = m
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := res.cases[PluralOther]; !ok {
+		p.pos = from
+		return nil, p.errorf("plural argument %q has no case other", name)
+	}
+	return res, nil
+}
+
+func (p *msgParser) parseSelect(name string, inPlural bool) (msgNode, error) {
+	res := msgSelect{
+		name:  name,
+		cases: make(map[string]message),
+	}
+
+	p.skipSpace()
+	from := p.pos
+	err := p.parseCases(inPlural, func(key string, m message) error {
+		res.cases[key] = m
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, ok := res.cases["other"]; !ok {
+		p.pos = from
+		return nil, p.errorf("select argument %q has no case other", name)
+	}
+	return res, nil
+}
+
+// msgRenderer holds state of message rendering.
+type msgRenderer struct {
+	sb strings.Builder
+	// lang is the language of the message, it defines plural rules.
+	lang Index
+	// locale is the requested language, it defines formatting of values.
+	locale  Index
+	args    args
+	missing []string
+	// pound holds value of # in the current plural case.
+	pound string
+}
+
+func (r *msgRenderer) arg(name string) (interface{}, bool) {
+	v, ok := r.args.get(name)
+	if !ok {
+		r.missing = appendUniqueString(r.missing, name)
+	}
+	return v, ok
+}
+
+// render renders the message written in the language lang, values are
+// formatted according to locale. Returns names of missing arguments and names
+// of map arguments not referenced by the message, including arguments of
+// plural and select cases not rendered.
+func (m message) render(lang, locale Index, a args) (string, []string, []string) {
+	r := msgRenderer{
+		lang:   lang,
+		locale: locale,
+		args:   a,
+	}
+	m.renderTo(&r)
+
+	referenced := make(map[string]bool)
+	m.collectArgs(referenced)
+	return r.sb.String(), r.missing, unusedArgs(a, referenced)
+}
+
+// collectArgs adds names of all arguments of the message to names.
+func (m message) collectArgs(names map[string]bool) {
+	for _, n := range m {
+		switch x := n.(type) {
+		case msgArg:
+			names[x.name] = true
+		case msgPlural:
+			names[x.name] = true
+			for _, c := range x.exact {
+				c.collectArgs(names)
+			}
+			for _, c := range x.cases {
+				c.collectArgs(names)
+			}
+		case msgSelect:
+			names[x.name] = true
+			for _, c := range x.cases {
+				c.collectArgs(names)
+			}
+		}
+	}
+}
+
+func (m message) renderTo(r *msgRenderer) {
+	for _, n := range m {
+		n.render(r)
+	}
+}
+
+func (t msgText) render(r *msgRenderer) {
+	r.sb.WriteString(string(t))
+}
+
+func (msgPound) render(r *msgRenderer) {
+	r.sb.WriteString(r.pound)
+}
+
+func (a msgArg) render(r *msgRenderer) {
+	v, ok := r.arg(a.name)
+	if !ok {
+		r.sb.WriteString("{" + a.name + "}")
+		return
+	}
+	r.sb.WriteString(formatArg(r.locale, v, a.typ, a.style))
+}
+
+// formatArg formats value of a simple argument. Numbers, Money, dates
+// and durations are formatted according to the language, other values by fmt.Sprint.
+// Strings of a placeholder without type are kept as they are, time.Time
+// is formatted as a medium date.
+func formatArg(li Index, v interface{}, typ, style string) string {
+	switch x := v.(type) {
+	case string:
+		if typ == "" {
+			return x
+		}
+	case time.Time:
+		if typ == "" {
+			typ = "date"
+		}
+	}
+
+	switch typ {
+	case "", "number":
+		if s, err := formatNumberArg(li, v, style); err == nil {
+			return s
+		}
+	case "date", "time", "relative":
+		if s, err := formatDateArg(li, v, typ, style); err == nil {
+			return s
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+func (pl msgPlural) render(r *msgRenderer) {
+	v, ok := r.arg(pl.name)
+
+	x, isNumber := toFloat(v)
+	n := fmt.Sprint(v)
+	if !ok {
+		n = "{" + pl.name + "}"
+	} else if isNumber && pl.offset != 0 {
+		n = strconv.FormatFloat(x-pl.offset, 'f', -1, 64)
+	}
+
+	m := pl.cases[PluralOther]
+	if exact, ok := pl.exact[x]; ok && isNumber {
+		m = exact
+	} else if o, err := newOperands(n); err == nil {
+		rules := cardinalRules
+		if pl.ordinal {
+			rules = ordinalRules
+		}
+		if c, ok := pl.cases[findRule(rules, r.lang)(o)]; ok {
+			m = c
+		}
+	}
+
+	saved := r.pound
+	r.pound = n
+	if isNumber {
+		r.pound, _ = FormatNumber(r.locale, n)
+	}
+	m.renderTo(r)
+	r.pound = saved
+}
+
+func (s msgSelect) render(r *msgRenderer) {
+	v, _ := r.arg(s.name)
+	m, ok := s.cases[fmt.Sprint(v)]
+	if !ok {
+		m = s.cases["other"]
+	}
+	m.renderTo(r)
+}
+
+// toFloat converts a number of any numeric type to float64.
+func toFloat(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(n), 64)
+		return f, err == nil
+	}
+	return 0, false
+}
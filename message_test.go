@@ -0,0 +1,137 @@
+package language
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCompileMessage(t *testing.T) {
+	valid := []string{
+		"",
+		"plain text",
+		"Hello, {name}!",
+		"{ name }",
+		"It''s {n, number}",
+		"'{literal}' braces",
+		"{count, plural, =0 {none} one {# item} other {# items}}",
+		"{count, plural, offset:1 =0 {nobody} =1 {{host}} other {{host} and # others}}",
+		"{gender, select, female {She} male {He} other {They}} liked {count, plural, one {# post} other {# posts}}",
+		"{g, select, female {{n, plural, one {her # file} other {her # files}}} other {{n, plural, other {their # files}}}}",
+		"{d, date, short} {t, time} {x, number, percent}",
+	}
+	for _, s := range valid {
+		if _, err := compileMessage(s); err != nil {
+			t.Errorf("%q: unexpected error %v", s, err)
+		}
+	}
+
+	invalid := []struct {
+		s      string
+		offset int
+	}{
+		{"Hello, {name", 12},
+		{"Hello, name}", 11},
+		{"{}", 1},
+		{"{ not a name }", 6},
+		{"{n, unknown}", 4},
+		{"{n, plural, one {x}}", 12},
+		{"{n, plural, few {x} other {y}", 29},
+		{"{n, plural, =x {a} other {b}}", 12},
+		{"{n, plural, lots {a} other {b}}", 12},
+		{"{g, select, male {He}}", 12},
+		{"{g, select, other {unclosed}", 28},
+		{"{n, number, {x}}", 12},
+	}
+	for _, tc := range invalid {
+		_, err := compileMessage(tc.s)
+		me, ok := err.(*msgError)
+		if !ok {
+			t.Errorf("%q: expected *msgError, got %v", tc.s, err)
+			continue
+		}
+		if me.Offset != tc.offset {
+			t.Errorf("%q: expected offset %d, got %d (%v)", tc.s, tc.offset, me.Offset, me)
+		}
+	}
+}
+
+func TestMessageFormat(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.i18n": {Data: []byte(strings.Join([]string{
+			"Liked={gender, select, female {She} male {He} other {They}} liked {count, plural, =0 {nothing} one {# post} other {# posts}}.",
+			"Party={guests, plural, offset:1 =0 {{host} is alone.} =1 {{host} invites {guest}.} one {{host} invites {guest} and # other.} other {{host} invites {guest} and # others.}}",
+			"Quote=It''s '{literal}' and {name}",
+			"Files={count, plural, one {# file} other {# files}}",
+		}, "\n"))},
+		"ru.i18n": {Data: []byte("Files={count, plural, one {# файл} few {# файла} many {# файлов} other {# файла}}\n")},
+	}
+
+	c := New(WithPrimaryLanguage(ToIndex("en")), WithMessageFormat())
+	if err := c.AddFileByMaskFS(fsys, ".", "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	type m = map[string]interface{}
+	cases := []struct {
+		lang string
+		id   string
+		args interface{}
+		exp  string
+	}{
+		{"en", "Liked", m{"gender": "female", "count": 1}, "She liked 1 post."},
+		{"en", "Liked", m{"gender": "x", "count": 0}, "They liked nothing."},
+		{"en", "Liked", m{"gender": "male", "count": 5}, "He liked 5 posts."},
+		{"en", "Party", m{"guests": 0, "host": "Ann", "guest": "Bob"}, "Ann is alone."},
+		{"en", "Party", m{"guests": 1, "host": "Ann", "guest": "Bob"}, "Ann invites Bob."},
+		{"en", "Party", m{"guests": 2, "host": "Ann", "guest": "Bob"}, "Ann invites Bob and 1 other."},
+		{"en", "Party", m{"guests": 5, "host": "Ann", "guest": "Bob"}, "Ann invites Bob and 4 others."},
+		{"en", "Quote", m{"name": "x"}, "It's {literal} and x"},
+		{"ru", "Files", m{"count": 22}, "22 файла"},
+		{"ru", "Files", m{"count": 25}, "25 файлов"},
+		{"de", "Files", m{"count": 1}, "1 file"},
+	}
+
+	for _, tc := range cases {
+		cr := c.Lang(ToIndex(tc.lang))
+		s, err := cr.Format(tc.id, tc.args)
+		if err != nil {
+			t.Errorf("%s.%s: unexpected error %v", tc.lang, tc.id, err)
+		}
+		if s != tc.exp {
+			t.Errorf("%s.%s: expected '%s', got '%s'", tc.lang, tc.id, tc.exp, s)
+		}
+	}
+
+	cr := c.Lang(ToIndex("en"))
+	s, err := cr.Format("Liked", m{"gender": "female", "extra": 1})
+	if s != "She liked {count} posts." {
+		t.Errorf("unexpected result '%s'", s)
+	}
+	ae, ok := err.(*ArgumentError)
+	if !ok || len(ae.Missing) != 1 || ae.Missing[0] != "count" || len(ae.Extra) != 1 || ae.Extra[0] != "extra" {
+		t.Errorf("unexpected error %v", err)
+	}
+}
+
+func TestMessageFormatLoadError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.i18n": {Data: []byte("# comment\nOk=Fine\nBroken=Hello, {name\n")},
+	}
+
+	c := New(WithMessageFormat())
+	if err := c.AddFilesFS(fsys, "en.i18n"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := c.ReadRegisteredFiles()
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if !strings.HasPrefix(err.Error(), "en.i18n:3: Broken:") {
+		t.Errorf("unexpected error %v", err)
+	}
+}
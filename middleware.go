@@ -0,0 +1,152 @@
+package language
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// MiddlewareOption defines options of Middleware.
+type MiddlewareOption struct {
+	sources []languageSource
+}
+
+// languageSource returns a loaded language requested by r, Unknown if r has none.
+type languageSource struct {
+	// vary holds request header the source depends on.
+	vary  string
+	match func(r *http.Request, langs []Index) Index
+}
+
+// WithQueryParam makes Middleware take the language from query parameter name: ?lang=de.
+func WithQueryParam(name string) func(o *MiddlewareOption) {
+	return func(o *MiddlewareOption) {
+		o.sources = append(o.sources, languageSource{
+			match: func(r *http.Request, langs []Index) Index {
+				return matchTag(r.URL.Query().Get(name), langs)
+			},
+		})
+	}
+}
+
+// WithCookie makes Middleware take the language from cookie name.
+func WithCookie(name string) func(o *MiddlewareOption) {
+	return func(o *MiddlewareOption) {
+		o.sources = append(o.sources, languageSource{
+			vary: "Cookie",
+			match: func(r *http.Request, langs []Index) Index {
+				ck, err := r.Cookie(name)
+				if err != nil {
+					return Unknown
+				}
+				return matchTag(ck.Value, langs)
+			},
+		})
+	}
+}
+
+// WithPathPrefix makes Middleware take the language from the first segment
+// of the path: /de/orders. The path is passed to the next handler unchanged.
+func WithPathPrefix() func(o *MiddlewareOption) {
+	return func(o *MiddlewareOption) {
+		o.sources = append(o.sources, languageSource{
+			match: func(r *http.Request, langs []Index) Index {
+				seg := strings.TrimPrefix(r.URL.Path, "/")
+				if i := strings.IndexByte(seg, '/'); i != -1 {
+					seg = seg[:i]
+				}
+				return matchTag(seg, langs)
+			},
+		})
+	}
+}
+
+// WithAcceptLanguage makes Middleware negotiate the language from Accept-Language header.
+func WithAcceptLanguage() func(o *MiddlewareOption) {
+	return func(o *MiddlewareOption) {
+		o.sources = append(o.sources, languageSource{
+			vary: "Accept-Language",
+			match: func(r *http.Request, langs []Index) Index {
+				return Negotiate(r.Header.Get("Accept-Language"), langs, Unknown)
+			},
+		})
+	}
+}
+
+// matchTag returns the loaded language matching a single language tag.
+func matchTag(tag string, langs []Index) Index {
+	tag = strings.TrimSpace(tag)
+	if tag == "" || tag == "*" || strings.ContainsAny(tag, ",;") {
+		return Unknown
+	}
+	return matchRange(tag, langs, nil)
+}
+
+type contextKey struct{}
+
+type contextValue struct {
+	lang Index
+	cr   ContainerRequest
+}
+
+// Middleware returns HTTP middleware selecting the language of a request.
+// Sources are checked in the order of options, the first one holding a loaded
+// language wins; without options Accept-Language header is used. The primary
+// language is selected if no source matches. The language and the request of
+// the container are stored in the request context (see FromContext and
+// RequestFromContext), Content-Language and Vary headers are set.
+//
+//	mw := c.Middleware(language.WithQueryParam("lang"), language.WithCookie("lang"), language.WithAcceptLanguage())
+//	http.ListenAndServe(":8080", mw(mux))
+func (c *Container) Middleware(fn ...func(o *MiddlewareOption)) func(http.Handler) http.Handler {
+	var cfg MiddlewareOption
+	for _, f := range fn {
+		f(&cfg)
+	}
+	if len(cfg.sources) == 0 {
+		WithAcceptLanguage()(&cfg)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			langs := c.Languages()
+			li := Unknown
+			for _, s := range cfg.sources {
+				if s.vary != "" {
+					w.Header().Add("Vary", s.vary)
+				}
+				if li = s.match(r, langs); li != Unknown {
+					break
+				}
+			}
+			if li == Unknown {
+				li = c.cfg.primaryLanguage
+			}
+
+			if li != Unknown {
+				w.Header().Set("Content-Language", IndexToCode(li))
+			}
+			next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), c.Lang(li))))
+		})
+	}
+}
+
+// NewContext returns a copy of ctx holding request cr and its language.
+func NewContext(ctx context.Context, cr ContainerRequest) context.Context {
+	return context.WithValue(ctx, contextKey{}, contextValue{lang: cr.lang, cr: cr})
+}
+
+// FromContext returns the language stored in ctx by Middleware, Unknown if ctx has none.
+func FromContext(ctx context.Context) Index {
+	v, ok := ctx.Value(contextKey{}).(contextValue)
+	if !ok {
+		return Unknown
+	}
+	return v.lang
+}
+
+// RequestFromContext returns the request of the container stored in ctx by Middleware.
+func RequestFromContext(ctx context.Context) (ContainerRequest, bool) {
+	v, ok := ctx.Value(contextKey{}).(contextValue)
+	return v.cr, ok
+}
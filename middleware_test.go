@@ -0,0 +1,100 @@
+package language
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMiddleware(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.i18n": {Data: []byte("Hello=Hello\n")},
+		"de.i18n": {Data: []byte("Hello=Hallo\n")},
+		"fr.i18n": {Data: []byte("Hello=Bonjour\n")},
+	}
+	c := New(WithPrimaryLanguage(ToIndex("en")))
+	if err := c.AddFilesFS(fsys, "en.i18n", "de.i18n", "fr.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	var got string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cr, ok := RequestFromContext(r.Context())
+		if !ok {
+			t.Error("expected request in context")
+		}
+		got = IndexToCode(FromContext(r.Context())) + " " + cr.Value("Hello")
+	})
+
+	h := c.Middleware(WithQueryParam("lang"), WithCookie("lang"), WithPathPrefix(), WithAcceptLanguage())(next)
+
+	cases := []struct {
+		name   string
+		path   string
+		cookie string
+		accept string
+		exp    string
+		vary   []string
+	}{
+		{"Query", "/de/page?lang=fr", "de", "de", "fr Bonjour", nil},
+		{"QueryRegion", "/?lang=de-AT", "", "", "de Hallo", nil},
+		{"UnknownQuery", "/?lang=it", "fr", "", "fr Bonjour", []string{"Cookie"}},
+		{"Path", "/de/page", "", "fr", "de Hallo", []string{"Cookie"}},
+		{"PathOnly", "/fr", "", "", "fr Bonjour", []string{"Cookie"}},
+		{"Header", "/orders", "", "it, fr;q=0.5", "fr Bonjour", []string{"Cookie", "Accept-Language"}},
+		{"Primary", "/orders", "", "it", "en Hello", []string{"Cookie", "Accept-Language"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tc.path, nil)
+			if tc.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: "lang", Value: tc.cookie})
+			}
+			if tc.accept != "" {
+				r.Header.Set("Accept-Language", tc.accept)
+			}
+			w := httptest.NewRecorder()
+			got = ""
+			h.ServeHTTP(w, r)
+
+			if got != tc.exp {
+				t.Errorf("expected %q, got %q", tc.exp, got)
+			}
+			if exp := tc.exp[:2]; w.Header().Get("Content-Language") != exp {
+				t.Errorf("expected Content-Language %q, got %q", exp, w.Header().Get("Content-Language"))
+			}
+			vary := w.Header().Values("Vary")
+			if len(vary) != len(tc.vary) {
+				t.Fatalf("expected Vary %q, got %q", tc.vary, vary)
+			}
+			for i := range vary {
+				if vary[i] != tc.vary[i] {
+					t.Errorf("expected Vary %q, got %q", tc.vary, vary)
+				}
+			}
+		})
+	}
+
+	t.Run("Default", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/?lang=fr", nil)
+		r.Header.Set("Accept-Language", "de")
+		c.Middleware()(next).ServeHTTP(httptest.NewRecorder(), r)
+		if exp := "de Hallo"; got != exp {
+			t.Errorf("expected %q, got %q", exp, got)
+		}
+	})
+
+	t.Run("EmptyContext", func(t *testing.T) {
+		if li := FromContext(context.Background()); li != Unknown {
+			t.Errorf("expected Unknown, got %v", li)
+		}
+		if _, ok := RequestFromContext(context.Background()); ok {
+			t.Error("expected no request")
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package language
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// MissingKey describes a key requested but not found.
+type MissingKey struct {
+	Lang Index
+	Key  string
+	// Hits is the number of requests of the key.
+	Hits int
+}
+
+// MissingKeyCollector collects unique missing keys per language.
+// Pass its method Handle to WithMissingKeyHandler:
+//
+//	mc := language.NewMissingKeyCollector()
+//	c := language.New(language.WithMissingKeyHandler(mc.Handle))
+type MissingKeyCollector struct {
+	mux  sync.Mutex
+	keys map[Index]map[string]int
+}
+
+// NewMissingKeyCollector creates an empty collector.
+func NewMissingKeyCollector() *MissingKeyCollector {
+	return &MissingKeyCollector{
+		keys: make(map[Index]map[string]int),
+	}
+}
+
+// Handle registers the missing key. It's safe for concurrent use.
+func (mc *MissingKeyCollector) Handle(lang Index, key string) {
+	mc.mux.Lock()
+	keys, ok := mc.keys[lang]
+	if !ok {
+		keys = make(map[string]int)
+		mc.keys[lang] = keys
+	}
+	keys[key]++
+	mc.mux.Unlock()
+}
+
+// Keys returns collected keys ordered by language code and key.
+func (mc *MissingKeyCollector) Keys() []MissingKey {
+	mc.mux.Lock()
+	res := make([]MissingKey, 0, len(mc.keys))
+	for lang, keys := range mc.keys {
+		for key, hits := range keys {
+			res = append(res, MissingKey{Lang: lang, Key: key, Hits: hits})
+		}
+	}
+	mc.mux.Unlock()
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Lang != res[j].Lang {
+			return IndexToCode(res[i].Lang) < IndexToCode(res[j].Lang)
+		}
+		return res[i].Key < res[j].Key
+	})
+	return res
+}
+
+// Reset removes all collected keys.
+func (mc *MissingKeyCollector) Reset() {
+	mc.mux.Lock()
+	mc.keys = make(map[Index]map[string]int)
+	mc.mux.Unlock()
+}
+
+// WriteReport writes collected keys grouped by language, a key per line:
+//
+//	de:
+//		Exit	3
+//		Help	1
+func (mc *MissingKeyCollector) WriteReport(w io.Writer) error {
+	lang := Unknown
+	for i, mk := range mc.Keys() {
+		if i == 0 || mk.Lang != lang {
+			lang = mk.Lang
+			if _, err := fmt.Fprintf(w, "%s:\n", IndexToCode(lang)); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(w, "\t%s\t%d\n", mk.Key, mk.Hits); err != nil {
+			return err
+		}
+	}
+	return nil
+}
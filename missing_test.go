@@ -0,0 +1,56 @@
+package language
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+func TestMissingKeyCollector(t *testing.T) {
+	en, de := ToIndex("en"), ToIndex("de")
+
+	mc := NewMissingKeyCollector()
+	c := New(WithPrimaryLanguage(en), WithMissingKeyHandler(mc.Handle))
+	if err := c.AddFiles(filepath.Join("testdata", "en.i18n"), filepath.Join("testdata", "de.i18n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	cr := c.Lang(de)
+	cr.Value("Save")
+	cr.Value("Exit") // found in primary language
+	cr.Value("Help")
+	cr.Hint("Help")
+	cr.WithStrategy(ReturnNotFoundVariable).Value("Exit")
+	c.Lang(en).Value("About")
+
+	keys := mc.Keys()
+	exp := []MissingKey{
+		{Lang: de, Key: "Exit", Hits: 1},
+		{Lang: de, Key: "Help", Hits: 2},
+		{Lang: en, Key: "About", Hits: 1},
+	}
+	if len(keys) != len(exp) {
+		t.Fatalf("expected %v, got %v", exp, keys)
+	}
+	for i := range exp {
+		if keys[i] != exp[i] {
+			t.Errorf("expected %v, got %v", exp[i], keys[i])
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := mc.WriteReport(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if s := "de:\n\tExit\t1\n\tHelp\t2\nen:\n\tAbout\t1\n"; buf.String() != s {
+		t.Errorf("expected report %q, got %q", s, buf.String())
+	}
+
+	mc.Reset()
+	if len(mc.Keys()) != 0 {
+		t.Error("expected no keys after Reset")
+	}
+}
@@ -0,0 +1,356 @@
+package language
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	nbsp       = "\u00a0"
+	narrowNbsp = "\u202f"
+)
+
+// numberFormat holds CLDR number symbols and patterns of a language.
+type numberFormat struct {
+	decimal string
+	group   string
+	// minGrouping is the minimum number of digits in the highest group,
+	// 2 means 1234 is not grouped, but 12 345 is.
+	minGrouping int
+	// percent and currency are CLDR patterns, the number part is
+	// replaced by "#", ¤ is the currency symbol.
+	percent  string
+	currency string
+	// symbols holds currency symbols used in the language instead of defaults.
+	symbols map[string]string
+}
+
+var numberFormats = map[string]numberFormat{
+	"root":  {decimal: ".", group: ",", minGrouping: 1, percent: "#%", currency: "¤" + nbsp + "#"},
+	"en":    {decimal: ".", group: ",", minGrouping: 1, percent: "#%", currency: "¤#"},
+	"ja":    {decimal: ".", group: ",", minGrouping: 1, percent: "#%", currency: "¤#", symbols: map[string]string{"JPY": "￥"}},
+	"zh":    {decimal: ".", group: ",", minGrouping: 1, percent: "#%", currency: "¤#", symbols: map[string]string{"CNY": "¥", "JPY": "JP¥"}},
+	"de":    {decimal: ",", group: ".", minGrouping: 1, percent: "#" + nbsp + "%", currency: "#" + nbsp + "¤"},
+	"de-AT": {decimal: ",", group: nbsp, minGrouping: 1, percent: "#" + nbsp + "%", currency: "¤" + nbsp + "#"},
+	"de-CH": {decimal: ".", group: "’", minGrouping: 1, percent: "#%", currency: "¤" + nbsp + "#"},
+	"nl":    {decimal: ",", group: ".", minGrouping: 1, percent: "#%", currency: "¤" + nbsp + "#"},
+	"fr":    {decimal: ",", group: narrowNbsp, minGrouping: 1, percent: "#" + narrowNbsp + "%", currency: "#" + nbsp + "¤", symbols: map[string]string{"USD": "$US"}},
+	"it":    {decimal: ",", group: ".", minGrouping: 1, percent: "#%", currency: "#" + nbsp + "¤"},
+	"es":    {decimal: ",", group: ".", minGrouping: 2, percent: "#" + nbsp + "%", currency: "#" + nbsp + "¤", symbols: map[string]string{"USD": "US$"}},
+	"pt":    {decimal: ",", group: ".", minGrouping: 1, percent: "#%", currency: "¤" + nbsp + "#", symbols: map[string]string{"USD": "US$", "BRL": "R$"}},
+	"pt-PT": {decimal: ",", group: nbsp, minGrouping: 2, percent: "#%", currency: "#" + nbsp + "¤", symbols: map[string]string{"USD": "US$"}},
+	"sv":    {decimal: ",", group: nbsp, minGrouping: 1, percent: "#" + nbsp + "%", currency: "#" + nbsp + "¤", symbols: map[string]string{"USD": "US$", "SEK": "kr"}},
+	"ru":    {decimal: ",", group: nbsp, minGrouping: 1, percent: "#" + nbsp + "%", currency: "#" + nbsp + "¤", symbols: map[string]string{"RUB": "₽"}},
+	"uk":    {decimal: ",", group: nbsp, minGrouping: 1, percent: "#%", currency: "#" + nbsp + "¤", symbols: map[string]string{"UAH": "₴", "USD": "USD"}},
+	"pl":    {decimal: ",", group: nbsp, minGrouping: 2, percent: "#%", currency: "#" + nbsp + "¤", symbols: map[string]string{"PLN": "zł"}},
+	"cs":    {decimal: ",", group: nbsp, minGrouping: 1, percent: "#" + nbsp + "%", currency: "#" + nbsp + "¤", symbols: map[string]string{"CZK": "Kč", "USD": "US$"}},
+	"sk":    {decimal: ",", group: nbsp, minGrouping: 1, percent: "#" + nbsp + "%", currency: "#" + nbsp + "¤", symbols: map[string]string{"USD": "USD"}},
+	"sr":    {decimal: ",", group: ".", minGrouping: 1, percent: "#%", currency: "#" + nbsp + "¤", symbols: map[string]string{"USD": "US$"}},
+	"hr":    {decimal: ",", group: ".", minGrouping: 1, percent: "#" + nbsp + "%", currency: "#" + nbsp + "¤", symbols: map[string]string{"USD": "USD"}},
+	"bs":    {decimal: ",", group: ".", minGrouping: 1, percent: "#" + nbsp + "%", currency: "#" + nbsp + "¤", symbols: map[string]string{"BAM": "KM", "USD": "US$"}},
+}
+
+// currencySymbols holds symbols used if a language has no own symbol.
+var currencySymbols = map[string]string{
+	"USD": "$",
+	"EUR": "€",
+	"GBP": "£",
+	"JPY": "¥",
+	"CNY": "CN¥",
+	"INR": "₹",
+	"ILS": "₪",
+	"KRW": "₩",
+	"VND": "₫",
+	"BRL": "R$",
+	"CAD": "CA$",
+	"AUD": "A$",
+}
+
+// currencyDigits holds ISO 4217 minor units of currencies not having 2 minor units.
+var currencyDigits = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0,
+	"KRW": 0, "PYG": 0, "RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0,
+	"XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// CurrencyDigits returns ISO 4217 minor units of the currency: 2 for EUR,
+// 0 for JPY, 3 for KWD. Unknown currencies have 2 minor units.
+func CurrencyDigits(code string) int {
+	if d, ok := currencyDigits[strings.ToUpper(code)]; ok {
+		return d
+	}
+	return 2
+}
+
+// Money is an amount in a currency. It's formatted by argument
+// {name, number, currency} of ICU MessageFormat.
+type Money struct {
+	Amount float64
+	// Currency is ISO 4217 code.
+	Currency string
+}
+
+// findNumberFormat returns number format of the language or of its closest parent.
+func findNumberFormat(li Index) numberFormat {
+	for tag := IndexToCode(li); tag != ""; tag = parentTag(tag) {
+		if nf, ok := numberFormats[tag]; ok {
+			return nf
+		}
+	}
+	return numberFormats["root"]
+}
+
+// currencySymbol returns symbol of the currency in the language li.
+func currencySymbol(li Index, code string) string {
+	code = strings.ToUpper(code)
+	for tag := IndexToCode(li); tag != ""; tag = parentTag(tag) {
+		if nf, ok := numberFormats[tag]; ok {
+			if s, ok := nf.symbols[code]; ok {
+				return s
+			}
+		}
+	}
+	if s, ok := currencySymbols[code]; ok {
+		return s
+	}
+	return code
+}
+
+// decimal is a number split into digits.
+type decimal struct {
+	negative bool
+	integer  string
+	fraction string
+}
+
+// newDecimal converts n into decimal rounding it half to even to maxFrac
+// fraction digits and removing trailing zeros beyond minFrac digits.
+func newDecimal(n interface{}, minFrac, maxFrac int) (decimal, error) {
+	var (
+		res decimal
+		s   string
+	)
+
+	switch x := n.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		s = fmt.Sprint(x)
+	case float32:
+		s = formatFloat(float64(x), maxFrac)
+	case float64:
+		s = formatFloat(x, maxFrac)
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(x), 64)
+		if err != nil {
+			return res, fmt.Errorf("invalid number %q", x)
+		}
+		s = formatFloat(f, maxFrac)
+	default:
+		return res, fmt.Errorf("unsupported number type %T", n)
+	}
+
+	if strings.HasPrefix(s, "-") {
+		res.negative = true
+		s = s[1:]
+	}
+
+	res.integer = s
+	if dot := strings.IndexByte(s, '.'); dot != -1 {
+		res.integer = s[:dot]
+		res.fraction = s[dot+1:]
+	}
+
+	for len(res.fraction) > minFrac && strings.HasSuffix(res.fraction, "0") {
+		res.fraction = res.fraction[:len(res.fraction)-1]
+	}
+	for len(res.fraction) < minFrac {
+		res.fraction += "0"
+	}
+
+	if res.negative && strings.Trim(res.integer+res.fraction, "0") == "" {
+		// -0.001 rounded to 0
+		res.negative = false
+	}
+	return res, nil
+}
+
+func formatFloat(f float64, maxFrac int) string {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return strconv.FormatFloat(f, 'f', maxFrac, 64)
+}
+
+// format returns digits with decimal and group separators of nf.
+func (d decimal) format(nf numberFormat) string {
+	var sb strings.Builder
+
+	digits := d.integer
+	if len(digits) >= 3+nf.minGrouping && isDigit(digits) {
+		first := len(digits) % 3
+		if first == 0 {
+			first = 3
+		}
+		sb.WriteString(digits[:first])
+		for i := first; i < len(digits); i += 3 {
+			sb.WriteString(nf.group)
+			sb.WriteString(digits[i : i+3])
+		}
+	} else {
+		sb.WriteString(digits)
+	}
+
+	if d.fraction != "" {
+		sb.WriteString(nf.decimal)
+		sb.WriteString(d.fraction)
+	}
+	return sb.String()
+}
+
+// applyPattern places formatted number into pattern of nf.
+// A symbol ending or starting with a letter is separated from the
+// adjacent number by no-break space: "USD 1.00" but "$1.00".
+func (d decimal) applyPattern(nf numberFormat, pattern string, symbol string) string {
+	if symbol != "" {
+		switch {
+		case strings.Contains(pattern, "¤#") && endsWithLetter(symbol):
+			pattern = strings.Replace(pattern, "¤#", "¤"+nbsp+"#", 1)
+		case strings.Contains(pattern, "#¤") && startsWithLetter(symbol):
+			pattern = strings.Replace(pattern, "#¤", "#"+nbsp+"¤", 1)
+		}
+	}
+
+	s := strings.Replace(pattern, "#", d.format(nf), 1)
+	s = strings.Replace(s, "¤", symbol, 1)
+	if d.negative {
+		s = "-" + s
+	}
+	return s
+}
+
+func startsWithLetter(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsLetter(r)
+}
+
+func endsWithLetter(s string) bool {
+	r, _ := utf8.DecodeLastRuneInString(s)
+	return unicode.IsLetter(r)
+}
+
+// FormatNumber formats number n using decimal and group separators of
+// the language li with up to 3 fraction digits: 1234.5678 is "1,234.568"
+// in English, "1.234,568" in German and "1 234,568" in Russian.
+// The number is an integer, a float or a decimal string.
+func FormatNumber(li Index, n interface{}) (string, error) {
+	return FormatDecimal(li, n, 0, 3)
+}
+
+// FormatDecimal formats number n like FormatNumber does with at least minFrac
+// and at most maxFrac fraction digits.
+func FormatDecimal(li Index, n interface{}, minFrac, maxFrac int) (string, error) {
+	if maxFrac < minFrac {
+		maxFrac = minFrac
+	}
+	d, err := newDecimal(n, minFrac, maxFrac)
+	if err != nil {
+		return "", err
+	}
+	nf := findNumberFormat(li)
+	return d.applyPattern(nf, "#", ""), nil
+}
+
+// FormatPercent formats ratio n as percent without fraction digits:
+// 0.256 is "26%" in English, "26 %" in German.
+func FormatPercent(li Index, n interface{}) (string, error) {
+	f, ok := toFloat(n)
+	if !ok {
+		return "", fmt.Errorf("unsupported number type %T", n)
+	}
+
+	d, err := newDecimal(f*100, 0, 0)
+	if err != nil {
+		return "", err
+	}
+	nf := findNumberFormat(li)
+	return d.applyPattern(nf, nf.percent, ""), nil
+}
+
+// FormatCurrency formats amount in the currency given by ISO 4217 code
+// with the number of fraction digits of the currency:
+// 1234.5 EUR is "€1,234.50" in English, "1.234,50 €" in German.
+func FormatCurrency(li Index, amount interface{}, currency string) (string, error) {
+	digits := CurrencyDigits(currency)
+	d, err := newDecimal(amount, digits, digits)
+	if err != nil {
+		return "", err
+	}
+	nf := findNumberFormat(li)
+	return d.applyPattern(nf, nf.currency, currencySymbol(li, currency)), nil
+}
+
+// Number formats n by FormatNumber in the language of the request.
+// Values of unsupported types are formatted by fmt.Sprint.
+func (cr ContainerRequest) Number(n interface{}) string {
+	s, err := FormatNumber(cr.lang, n)
+	if err != nil {
+		return fmt.Sprint(n)
+	}
+	return s
+}
+
+// Percent formats n by FormatPercent in the language of the request.
+// Values of unsupported types are formatted by fmt.Sprint.
+func (cr ContainerRequest) Percent(n interface{}) string {
+	s, err := FormatPercent(cr.lang, n)
+	if err != nil {
+		return fmt.Sprint(n)
+	}
+	return s
+}
+
+// Currency formats amount by FormatCurrency in the language of the request.
+// Values of unsupported types are formatted by fmt.Sprint.
+func (cr ContainerRequest) Currency(amount interface{}, currency string) string {
+	s, err := FormatCurrency(cr.lang, amount, currency)
+	if err != nil {
+		return fmt.Sprint(amount)
+	}
+	return s
+}
+
+// isNumberStyle returns true if style is supported by formatNumberArg.
+func isNumberStyle(style string) bool {
+	switch {
+	case style == "", style == "integer", style == "percent", style == "currency":
+		return true
+	case strings.HasPrefix(style, "::currency/"):
+		return len(style) > len("::currency/")
+	}
+	return false
+}
+
+// formatNumberArg formats argument of MessageFormat {name, number, style}.
+// Style is empty, "integer", "percent", "currency" for Money arguments
+// or "::currency/EUR" for plain numbers. Money is formatted as currency
+// regardless of style.
+func formatNumberArg(li Index, v interface{}, style string) (string, error) {
+	if m, ok := v.(Money); ok {
+		return FormatCurrency(li, m.Amount, m.Currency)
+	}
+
+	switch {
+	case style == "integer":
+		return FormatDecimal(li, v, 0, 0)
+	case style == "percent":
+		return FormatPercent(li, v)
+	case strings.HasPrefix(style, "::currency/"):
+		return FormatCurrency(li, v, style[len("::currency/"):])
+	}
+	return FormatNumber(li, v)
+}
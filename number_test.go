@@ -0,0 +1,178 @@
+package language
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestFormatNumber(t *testing.T) {
+	cases := []struct {
+		lang string
+		n    interface{}
+		exp  string
+	}{
+		{"en", 1234.5678, "1,234.568"},
+		{"en", 1234567, "1,234,567"},
+		{"en", -1234.5, "-1,234.5"},
+		{"en", 0.1, "0.1"},
+		{"en", "1000.50", "1,000.5"},
+		{"en", -0.0001, "0"},
+		{"de", 1234.5678, "1.234,568"},
+		{"de-AT", 1234.5, "1\u00a0234,5"},
+		{"de-CH", 1234.5, "1’234.5"},
+		{"ru", 1234.5678, "1\u00a0234,568"},
+		{"sr", 1234.5, "1.234,5"},
+		{"sr-Latn", 1234.5, "1.234,5"},
+		{"es", 1234, "1234"},
+		{"es", 12345, "12.345"},
+		{"pl", 1234, "1234"},
+		{"fr", 12345.5, "12\u202f345,5"},
+		{"xx", 1234.5, "1,234.5"},
+	}
+
+	for _, tc := range cases {
+		s, err := FormatNumber(ToIndex(tc.lang), tc.n)
+		if err != nil {
+			t.Errorf("%s %v: %v", tc.lang, tc.n, err)
+			continue
+		}
+		if s != tc.exp {
+			t.Errorf("%s %v: expected %q, got %q", tc.lang, tc.n, tc.exp, s)
+		}
+	}
+
+	if _, err := FormatNumber(ToIndex("en"), struct{}{}); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func TestFormatPercentCurrency(t *testing.T) {
+	percents := []struct {
+		lang string
+		n    interface{}
+		exp  string
+	}{
+		{"en", 0.256, "26%"},
+		{"de", 0.256, "26\u00a0%"},
+		{"ru", 1.5, "150\u00a0%"},
+		{"sr", 0.5, "50%"},
+		{"en", 123.45, "12,345%"},
+	}
+	for _, tc := range percents {
+		if s, err := FormatPercent(ToIndex(tc.lang), tc.n); err != nil || s != tc.exp {
+			t.Errorf("%s %v: expected %q, got %q (%v)", tc.lang, tc.n, tc.exp, s, err)
+		}
+	}
+
+	currencies := []struct {
+		lang     string
+		n        interface{}
+		currency string
+		exp      string
+	}{
+		{"en", 1234.5, "EUR", "€1,234.50"},
+		{"en", -1234.5, "USD", "-$1,234.50"},
+		{"en", 1234.5, "JPY", "¥1,234"},
+		{"en", 1235.5, "JPY", "¥1,236"},
+		{"en", 1.2346, "KWD", "KWD\u00a01.235"},
+		{"en", 10, "RUB", "RUB\u00a010.00"},
+		{"de", 1234.5, "EUR", "1.234,50\u00a0€"},
+		{"de-AT", 1234.5, "EUR", "€\u00a01\u00a0234,50"},
+		{"ru", 1234.5, "RUB", "1\u00a0234,50\u00a0₽"},
+		{"sr", 1234.5, "RSD", "1.234,50\u00a0RSD"},
+		{"sr", 5, "usd", "5,00\u00a0US$"},
+		{"cs", 100, "CZK", "100,00\u00a0Kč"},
+	}
+	for _, tc := range currencies {
+		if s, err := FormatCurrency(ToIndex(tc.lang), tc.n, tc.currency); err != nil || s != tc.exp {
+			t.Errorf("%s %v %s: expected %q, got %q (%v)", tc.lang, tc.n, tc.currency, tc.exp, s, err)
+		}
+	}
+
+	if d := CurrencyDigits("jpy"); d != 0 {
+		t.Errorf("expected 0 digits, got %d", d)
+	}
+	if d := CurrencyDigits("XYZ"); d != 2 {
+		t.Errorf("expected 2 digits, got %d", d)
+	}
+}
+
+func TestNumberArguments(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.i18n": {Data: []byte(strings.Join([]string{
+			"Total=Total: {sum, number, currency}",
+			"Price=Price: {price, number, ::currency/EUR}",
+			"Share={share, number, percent} of {count} users",
+			"Rounded={n, number, integer}",
+			"Files={count, plural, one {# file} other {# files}}",
+		}, "\n"))},
+		"de.i18n": {Data: []byte("Files={count, plural, one {# Datei} other {# Dateien}}\n")},
+	}
+
+	c := New(WithPrimaryLanguage(ToIndex("en")), WithMessageFormat())
+	if err := c.AddFileByMaskFS(fsys, ".", "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	type m = map[string]interface{}
+	cases := []struct {
+		lang string
+		id   string
+		args m
+		exp  string
+	}{
+		{"en", "Total", m{"sum": Money{Amount: 1234.5, Currency: "USD"}}, "Total: $1,234.50"},
+		{"de", "Total", m{"sum": Money{Amount: 1234.5, Currency: "USD"}}, "Total: 1.234,50\u00a0$"},
+		{"de", "Price", m{"price": 9.99}, "Price: 9,99\u00a0€"},
+		{"de", "Share", m{"share": 0.25, "count": 12000}, "25\u00a0% of 12.000 users"},
+		{"ru", "Rounded", m{"n": 1234.6}, "1\u00a0235"},
+		{"de", "Files", m{"count": 1500}, "1.500 Dateien"},
+	}
+
+	for _, tc := range cases {
+		cr := c.Lang(ToIndex(tc.lang))
+		if s, err := cr.Format(tc.id, tc.args); err != nil || s != tc.exp {
+			t.Errorf("%s.%s: expected %q, got %q (%v)", tc.lang, tc.id, tc.exp, s, err)
+		}
+	}
+
+	cr := c.Lang(ToIndex("de"))
+	if s := cr.Currency(5, "EUR"); s != "5,00\u00a0€" {
+		t.Errorf("expected %q, got %q", "5,00\u00a0€", s)
+	}
+	if s := cr.Number(0.5); s != "0,5" {
+		t.Errorf("expected %q, got %q", "0,5", s)
+	}
+	if s := cr.Percent(0.5); s != "50\u00a0%" {
+		t.Errorf("expected %q, got %q", "50\u00a0%", s)
+	}
+
+	if _, err := compileMessage("{n, number, fancy}"); err == nil {
+		t.Error("expected error for unsupported number style")
+	}
+
+	// placeholders without MessageFormat
+	c = New(WithPrimaryLanguage(ToIndex("en")))
+	plain := fstest.MapFS{
+		"en.i18n": {Data: []byte("Paid={name} paid {sum} for {count} items, code {code}\n")},
+	}
+	if err := c.AddFilesFS(plain, "en.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+	args := m{"name": "Anna", "sum": Money{Amount: 1234.5, Currency: "EUR"}, "count": 1500, "code": "10115"}
+	for lang, exp := range map[string]string{
+		"de": "Anna paid 1.234,50\u00a0€ for 1.500 items, code 10115",
+		"ru": "Anna paid 1\u00a0234,50\u00a0€ for 1\u00a0500 items, code 10115",
+	} {
+		if s, err := c.Lang(ToIndex(lang)).Format("Paid", args); err != nil || s != exp {
+			t.Errorf("%s: expected %q, got %q (%v)", lang, exp, s, err)
+		}
+	}
+}
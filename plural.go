@@ -0,0 +1,609 @@
+package language
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PluralCategory is a CLDR plural category.
+type PluralCategory string
+
+const (
+	PluralZero  PluralCategory = "zero"
+	PluralOne   PluralCategory = "one"
+	PluralTwo   PluralCategory = "two"
+	PluralFew   PluralCategory = "few"
+	PluralMany  PluralCategory = "many"
+	PluralOther PluralCategory = "other"
+)
+
+// IsValid returns true if pc is one of CLDR plural categories.
+func (pc PluralCategory) IsValid() bool {
+	switch pc {
+	case PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther:
+		return true
+	}
+	return false
+}
+
+// operands holds CLDR plural operands of a number.
+type operands struct {
+	n float64 // absolute value
+	i int64   // integer digits of n
+	v int     // number of visible fraction digits, with trailing zeros
+	f int64   // visible fraction digits, with trailing zeros
+	t int64   // visible fraction digits, without trailing zeros
+}
+
+// newOperands calculates operands of an integer, a float or a decimal string.
+// A decimal string keeps trailing zeros: "1.50" has v = 2.
+func newOperands(x interface{}) (operands, error) {
+	var s string
+	switch n := x.(type) {
+	case int:
+		s = strconv.FormatInt(int64(n), 10)
+	case int8:
+		s = strconv.FormatInt(int64(n), 10)
+	case int16:
+		s = strconv.FormatInt(int64(n), 10)
+	case int32:
+		s = strconv.FormatInt(int64(n), 10)
+	case int64:
+		s = strconv.FormatInt(n, 10)
+	case uint:
+		s = strconv.FormatUint(uint64(n), 10)
+	case uint8:
+		s = strconv.FormatUint(uint64(n), 10)
+	case uint16:
+		s = strconv.FormatUint(uint64(n), 10)
+	case uint32:
+		s = strconv.FormatUint(uint64(n), 10)
+	case uint64:
+		s = strconv.FormatUint(n, 10)
+	case float32:
+		s = strconv.FormatFloat(float64(n), 'f', -1, 32)
+	case float64:
+		s = strconv.FormatFloat(n, 'f', -1, 64)
+	case string:
+		s = strings.TrimSpace(n)
+	default:
+		return operands{}, fmt.Errorf("unsupported number type %T", x)
+	}
+	return parseOperands(s)
+}
+
+func parseOperands(s string) (operands, error) {
+	var o operands
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsInf(n, 0) || math.IsNaN(n) {
+		return o, fmt.Errorf("invalid number %q", s)
+	}
+	o.n = math.Abs(n)
+	o.i = int64(o.n)
+
+	s = strings.TrimLeft(s, "+-")
+	if dot := strings.IndexByte(s, '.'); dot != -1 {
+		frac := s[dot+1:]
+		if !isDigit(frac) {
+			return o, fmt.Errorf("invalid number %q", s)
+		}
+		o.v = len(frac)
+		if len(frac) > 18 {
+			frac = frac[:18]
+		}
+		if frac != "" {
+			o.f, _ = strconv.ParseInt(frac, 10, 64)
+		}
+		if trimmed := strings.TrimRight(frac, "0"); trimmed != "" {
+			o.t, _ = strconv.ParseInt(trimmed, 10, 64)
+		}
+	}
+	return o, nil
+}
+
+// pluralRule returns plural category of a number.
+type pluralRule func(o operands) PluralCategory
+
+func inRange(x, from, to int64) bool {
+	return x >= from && x <= to
+}
+
+// is returns true if n equals x, 1.0 and 1.00 are equal to 1.
+func (o operands) is(x float64) bool {
+	return o.n == x
+}
+
+// mod returns n % m, preserving fraction digits of n.
+func (o operands) mod(m float64) float64 {
+	return math.Mod(o.n, m)
+}
+
+func ruleOther(o operands) PluralCategory {
+	return PluralOther
+}
+
+// ruleOneI1V0 covers en, de, nl, sv, fi, et and others: one is 1 without fraction.
+func ruleOneI1V0(o operands) PluralCategory {
+	if o.i == 1 && o.v == 0 {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// ruleOneN1 covers es, el, hu, tr, bg: one is n = 1, 1.0, 1.00.
+func ruleOneN1(o operands) PluralCategory {
+	if o.is(1) {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+// millions returns true if the number is a multiple of million (many category in romance languages).
+func millions(o operands) bool {
+	return o.v == 0 && o.i != 0 && o.i%1000000 == 0
+}
+
+var cardinalRules = map[string]pluralRule{
+	"root": ruleOther,
+	"ja":   ruleOther,
+	"zh":   ruleOther,
+	"ko":   ruleOther,
+	"vi":   ruleOther,
+	"th":   ruleOther,
+	"id":   ruleOther,
+	"ms":   ruleOther,
+
+	"en": ruleOneI1V0,
+	"de": ruleOneI1V0,
+	"nl": ruleOneI1V0,
+	"sv": ruleOneI1V0,
+	"fi": ruleOneI1V0,
+	"et": ruleOneI1V0,
+	"nb": ruleOneN1,
+	"no": ruleOneN1,
+	"el": ruleOneN1,
+	"hu": ruleOneN1,
+	"tr": ruleOneN1,
+	"bg": ruleOneN1,
+
+	"da": func(o operands) PluralCategory {
+		if o.is(1) || (o.t != 0 && (o.i == 0 || o.i == 1)) {
+			return PluralOne
+		}
+		return PluralOther
+	},
+
+	"es": func(o operands) PluralCategory {
+		switch {
+		case o.is(1):
+			return PluralOne
+		case millions(o):
+			return PluralMany
+		}
+		return PluralOther
+	},
+
+	"it": func(o operands) PluralCategory {
+		switch {
+		case o.i == 1 && o.v == 0:
+			return PluralOne
+		case millions(o):
+			return PluralMany
+		}
+		return PluralOther
+	},
+
+	"fr": func(o operands) PluralCategory {
+		switch {
+		case o.i == 0 || o.i == 1:
+			return PluralOne
+		case millions(o):
+			return PluralMany
+		}
+		return PluralOther
+	},
+
+	"pt": func(o operands) PluralCategory {
+		switch {
+		case o.i == 0 || o.i == 1:
+			return PluralOne
+		case millions(o):
+			return PluralMany
+		}
+		return PluralOther
+	},
+
+	"pt-PT": func(o operands) PluralCategory {
+		switch {
+		case o.i == 1 && o.v == 0:
+			return PluralOne
+		case millions(o):
+			return PluralMany
+		}
+		return PluralOther
+	},
+
+	"ru": ruleEastSlavic,
+	"uk": ruleEastSlavic,
+
+	"sr": ruleSerboCroatian,
+	"hr": ruleSerboCroatian,
+	"bs": ruleSerboCroatian,
+	"sh": ruleSerboCroatian,
+
+	"cs": ruleCzech,
+	"sk": ruleCzech,
+
+	"pl": func(o operands) PluralCategory {
+		i10, i100 := o.i%10, o.i%100
+		switch {
+		case o.i == 1 && o.v == 0:
+			return PluralOne
+		case o.v == 0 && inRange(i10, 2, 4) && !inRange(i100, 12, 14):
+			return PluralFew
+		case o.v == 0 && (i10 == 0 || i10 == 1 || inRange(i10, 5, 9) || inRange(i100, 12, 14)):
+			return PluralMany
+		}
+		return PluralOther
+	},
+
+	"sl": func(o operands) PluralCategory {
+		i100 := o.i % 100
+		switch {
+		case o.v == 0 && i100 == 1:
+			return PluralOne
+		case o.v == 0 && i100 == 2:
+			return PluralTwo
+		case o.v == 0 && inRange(i100, 3, 4) || o.v != 0:
+			return PluralFew
+		}
+		return PluralOther
+	},
+
+	"ro": func(o operands) PluralCategory {
+		switch {
+		case o.i == 1 && o.v == 0:
+			return PluralOne
+		case o.v != 0 || o.is(0) || (!o.is(1) && inRange(o.i%100, 1, 19) && o.n == float64(o.i)):
+			return PluralFew
+		}
+		return PluralOther
+	},
+
+	"lt": func(o operands) PluralCategory {
+		n10, n100 := o.mod(10), o.mod(100)
+		switch {
+		case n10 == 1 && !(n100 >= 11 && n100 <= 19):
+			return PluralOne
+		case n10 >= 2 && n10 <= 9 && n10 == math.Trunc(n10) && !(n100 >= 11 && n100 <= 19):
+			return PluralFew
+		case o.f != 0:
+			return PluralMany
+		}
+		return PluralOther
+	},
+
+	"lv": func(o operands) PluralCategory {
+		n10, n100 := o.mod(10), o.mod(100)
+		f10, f100 := o.f%10, o.f%100
+		switch {
+		case n10 == 0 || (n100 >= 11 && n100 <= 19) || (o.v == 2 && inRange(f100, 11, 19)):
+			return PluralZero
+		case (n10 == 1 && n100 != 11) || (o.v == 2 && f10 == 1 && f100 != 11) || (o.v != 2 && f10 == 1):
+			return PluralOne
+		}
+		return PluralOther
+	},
+
+	"he": func(o operands) PluralCategory {
+		switch {
+		case (o.i == 1 && o.v == 0) || (o.i == 0 && o.v != 0):
+			return PluralOne
+		case o.i == 2 && o.v == 0:
+			return PluralTwo
+		}
+		return PluralOther
+	},
+
+	"ar": func(o operands) PluralCategory {
+		n100 := o.mod(100)
+		switch {
+		case o.is(0):
+			return PluralZero
+		case o.is(1):
+			return PluralOne
+		case o.is(2):
+			return PluralTwo
+		case n100 >= 3 && n100 <= 10 && n100 == math.Trunc(n100):
+			return PluralFew
+		case n100 >= 11 && n100 <= 99 && n100 == math.Trunc(n100):
+			return PluralMany
+		}
+		return PluralOther
+	},
+
+	"hi": ruleOneI0N1,
+	"bn": ruleOneI0N1,
+	"fa": ruleOneI0N1,
+}
+
+// ruleOneI0N1 covers hi, bn, fa: one is 0..1.
+func ruleOneI0N1(o operands) PluralCategory {
+	if o.i == 0 || o.is(1) {
+		return PluralOne
+	}
+	return PluralOther
+}
+
+func ruleEastSlavic(o operands) PluralCategory {
+	if o.v != 0 {
+		return PluralOther
+	}
+	i10, i100 := o.i%10, o.i%100
+	switch {
+	case i10 == 1 && i100 != 11:
+		return PluralOne
+	case inRange(i10, 2, 4) && !inRange(i100, 12, 14):
+		return PluralFew
+	}
+	return PluralMany
+}
+
+func ruleSerboCroatian(o operands) PluralCategory {
+	i10, i100 := o.i%10, o.i%100
+	f10, f100 := o.f%10, o.f%100
+	switch {
+	case o.v == 0 && i10 == 1 && i100 != 11 || f10 == 1 && f100 != 11:
+		return PluralOne
+	case o.v == 0 && inRange(i10, 2, 4) && !inRange(i100, 12, 14) ||
+		inRange(f10, 2, 4) && !inRange(f100, 12, 14):
+		return PluralFew
+	}
+	return PluralOther
+}
+
+func ruleCzech(o operands) PluralCategory {
+	switch {
+	case o.i == 1 && o.v == 0:
+		return PluralOne
+	case inRange(o.i, 2, 4) && o.v == 0:
+		return PluralFew
+	case o.v != 0:
+		return PluralMany
+	}
+	return PluralOther
+}
+
+// findRule returns the rule of the language or of its closest parent,
+// the rule of root if none is found.
+func findRule(rules map[string]pluralRule, li Index) pluralRule {
+	for tag := IndexToCode(li); tag != ""; tag = parentTag(tag) {
+		if r, ok := rules[tag]; ok {
+			return r
+		}
+	}
+	return rules["root"]
+}
+
+// Cardinal returns CLDR cardinal plural category of number n in the language li.
+// The number is an integer, a float or a decimal string, "1.0" differs
+// from "1" in some languages.
+// Languages without known rules use category other for all numbers.
+func Cardinal(li Index, n interface{}) (PluralCategory, error) {
+	o, err := newOperands(n)
+	if err != nil {
+		return PluralOther, err
+	}
+	return findRule(cardinalRules, li)(o), nil
+}
+
+// ordinalPrefix precedes category of ordinal forms: Place[ordinal:one].
+const ordinalPrefix = "ordinal:"
+
+// Ordinal returns CLDR ordinal plural category of number n in the language li:
+// PluralOne for 1, 21, PluralTwo for 2, 22, PluralFew for 3, 23 in English.
+// Languages without known rules use category other for all numbers.
+func Ordinal(li Index, n interface{}) (PluralCategory, error) {
+	o, err := newOperands(n)
+	if err != nil {
+		return PluralOther, err
+	}
+	return findRule(ordinalRules, li)(o), nil
+}
+
+var ordinalRules = map[string]pluralRule{
+	"root": ruleOther,
+
+	"en": func(o operands) PluralCategory {
+		n10, n100 := o.mod(10), o.mod(100)
+		switch {
+		case n10 == 1 && n100 != 11:
+			return PluralOne
+		case n10 == 2 && n100 != 12:
+			return PluralTwo
+		case n10 == 3 && n100 != 13:
+			return PluralFew
+		}
+		return PluralOther
+	},
+
+	"fr": ruleOneN1,
+	"ro": ruleOneN1,
+	"ms": ruleOneN1,
+	"vi": ruleOneN1,
+
+	"hu": func(o operands) PluralCategory {
+		if o.is(1) || o.is(5) {
+			return PluralOne
+		}
+		return PluralOther
+	},
+
+	"sv": func(o operands) PluralCategory {
+		n10, n100 := o.mod(10), o.mod(100)
+		if (n10 == 1 || n10 == 2) && n100 != 11 && n100 != 12 {
+			return PluralOne
+		}
+		return PluralOther
+	},
+
+	"it": func(o operands) PluralCategory {
+		if o.is(11) || o.is(8) || o.is(80) || o.is(800) {
+			return PluralMany
+		}
+		return PluralOther
+	},
+
+	"ca": func(o operands) PluralCategory {
+		switch {
+		case o.is(1) || o.is(3):
+			return PluralOne
+		case o.is(2):
+			return PluralTwo
+		case o.is(4):
+			return PluralFew
+		}
+		return PluralOther
+	},
+
+	"uk": func(o operands) PluralCategory {
+		if o.mod(10) == 3 && o.mod(100) != 13 {
+			return PluralFew
+		}
+		return PluralOther
+	},
+}
+
+// pluralKey returns key of the plural form: "Files[few]" or "Place[ordinal:few]".
+func pluralKey(id string, pc PluralCategory, ordinal bool) string {
+	if ordinal {
+		return id + "[" + ordinalPrefix + string(pc) + "]"
+	}
+	return id + "[" + string(pc) + "]"
+}
+
+// splitPluralKey splits key "Files[few]" into "Files" and PluralFew,
+// key "Place[ordinal:few]" into "Place", PluralFew and true.
+func splitPluralKey(k string) (id string, pc PluralCategory, ordinal bool, ok bool) {
+	if !strings.HasSuffix(k, "]") {
+		return k, "", false, false
+	}
+	from := strings.LastIndexByte(k, '[')
+	if from <= 0 {
+		return k, "", false, false
+	}
+
+	cat := k[from+1 : len(k)-1]
+	if strings.HasPrefix(cat, ordinalPrefix) {
+		cat = cat[len(ordinalPrefix):]
+		ordinal = true
+	}
+
+	pc = PluralCategory(cat)
+	if !pc.IsValid() {
+		return k, "", false, false
+	}
+	return k[:from], pc, ordinal, true
+}
+
+// Plural returns the plural form of id matching number n. Forms are declared
+// in .i18n files by keys with category in brackets:
+//
+//	Files[one]=# файл
+//	Files[few]=# файла
+//	Files[many]=# файлов
+//	Files[other]=# файла
+//
+// Sign # in the form is replaced by n formatted by FormatNumber. If the form of the category is not declared
+// the form "other" is used, then the value of the key without category.
+// Every language of the fallback chain is checked using its own plural rules.
+func (cr ContainerRequest) Plural(id string, n interface{}) string {
+	return cr.pluralForm(id, n, false)
+}
+
+// Ordinal returns the ordinal form of id matching number n. Forms are declared
+// in .i18n files by keys with prefix "ordinal:" before category:
+//
+//	Place[ordinal:one]=#st place
+//	Place[ordinal:two]=#nd place
+//	Place[ordinal:few]=#rd place
+//	Place[ordinal:other]=#th place
+//
+// Forms are selected the same way Plural does using CLDR ordinal rules.
+func (cr ContainerRequest) Ordinal(id string, n interface{}) string {
+	return cr.pluralForm(id, n, true)
+}
+
+func (cr ContainerRequest) pluralForm(id string, n interface{}, ordinal bool) string {
+	snap := cr.c.snapshot()
+	id = cr.trimID(id)
+	full := cr.ns + id
+
+	o, err := newOperands(n)
+	if err != nil {
+		return cr.notFound(id)
+	}
+
+	rules := cardinalRules
+	if ordinal {
+		rules = ordinalRules
+	}
+
+	for _, li := range cr.chain() {
+		pc := findRule(rules, li)(o)
+		for _, k := range []string{pluralKey(full, pc, ordinal), pluralKey(full, PluralOther, ordinal), full} {
+			if item, ok := cr.lookup(snap, li, k); ok {
+				num, err := FormatNumber(cr.lang, n)
+				if err != nil {
+					num = fmt.Sprint(n)
+				}
+				return replacePound(item.Value, num)
+			}
+		}
+	}
+
+	cr.missing(full)
+	return cr.notFound(id)
+}
+
+// replacePound replaces # of plural form s by num. A # followed by '{' or
+// following a letter or a digit is kept: "#{id}", "C#". \# is a literal #,
+// \\ is a backslash.
+func replacePound(s, num string) string {
+	if !strings.Contains(s, "#") {
+		return s
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && i+1 < len(s) && (s[i+1] == '#' || s[i+1] == '\\'):
+			sb.WriteByte(s[i+1])
+			i++
+		case s[i] == '#' && isPound(s, i):
+			sb.WriteString(num)
+		default:
+			sb.WriteByte(s[i])
+		}
+	}
+	return sb.String()
+}
+
+// isPound returns true if # at position i of s stands for the number.
+func isPound(s string, i int) bool {
+	if i+1 < len(s) && s[i+1] == '{' {
+		return false
+	}
+	if i > 0 {
+		r, _ := utf8.DecodeLastRuneInString(s[:i])
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,234 @@
+package language
+
+import (
+	"encoding/json"
+	"testing"
+	"testing/fstest"
+)
+
+func TestCardinal(t *testing.T) {
+	cases := []struct {
+		lang string
+		n    interface{}
+		exp  PluralCategory
+	}{
+		{"en", 1, PluralOne},
+		{"en", "1.0", PluralOther},
+		{"en", 2, PluralOther},
+		{"en", -1, PluralOne},
+		{"en-US", 1, PluralOne},
+		{"de", 0, PluralOther},
+		{"ru", 1, PluralOne},
+		{"ru", 2, PluralFew},
+		{"ru", 5, PluralMany},
+		{"ru", 11, PluralMany},
+		{"ru", 21, PluralOne},
+		{"ru", 22, PluralFew},
+		{"ru", 112, PluralMany},
+		{"ru", 1.5, PluralOther},
+		{"sr", 1, PluralOne},
+		{"sr", 3, PluralFew},
+		{"sr", 5, PluralOther},
+		{"sr", "1.1", PluralOne},
+		{"sr", "2.3", PluralFew},
+		{"sr-Latn", 21, PluralOne},
+		{"cs", 1, PluralOne},
+		{"cs", 4, PluralFew},
+		{"cs", 5, PluralOther},
+		{"cs", "1.5", PluralMany},
+		{"pl", 22, PluralFew},
+		{"pl", 25, PluralMany},
+		{"pl", 1, PluralOne},
+		{"fr", 0, PluralOne},
+		{"fr", 1.5, PluralOne},
+		{"fr", 1000000, PluralMany},
+		{"pt-PT", 0, PluralOther},
+		{"pt-BR", 0, PluralOne},
+		{"ar", 0, PluralZero},
+		{"ar", 2, PluralTwo},
+		{"ar", 103, PluralFew},
+		{"ar", 11, PluralMany},
+		{"ar", 100, PluralOther},
+		{"sl", 102, PluralTwo},
+		{"ja", 1, PluralOther},
+		{"xx", 1, PluralOther},
+		{"en", uint8(1), PluralOne},
+	}
+
+	for _, tc := range cases {
+		pc, err := Cardinal(ToIndex(tc.lang), tc.n)
+		if err != nil {
+			t.Errorf("%s %v: %v", tc.lang, tc.n, err)
+			continue
+		}
+		if pc != tc.exp {
+			t.Errorf("%s %v: expected %s, got %s", tc.lang, tc.n, tc.exp, pc)
+		}
+	}
+
+	for _, n := range []interface{}{"abc", "1.x", struct{}{}} {
+		if _, err := Cardinal(ToIndex("en"), n); err == nil {
+			t.Errorf("%v: expected error", n)
+		}
+	}
+}
+
+func TestPlural(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en.i18n": {Data: []byte("Files[one]=# file\nFiles[other]=# files\nFolders=Folders\n")},
+		"ru.i18n": {Data: []byte("Files[one]=# файл\nFiles[few]=# файла\nFiles[many]=# файлов\nFiles[other]=# файла\n")},
+		"cs.i18n": {Data: []byte("Files=soubory\n")},
+		"de.i18n": {Data: []byte("Order[one]=# Bestellung #{id}\nOrder[other]=# Bestellungen in C#, \\# und #\n")},
+	}
+
+	c := New(WithPrimaryLanguage(ToIndex("en")))
+	if err := c.AddFileByMaskFS(fsys, ".", "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		lang string
+		n    interface{}
+		exp  string
+	}{
+		{"en", 1, "1 file"},
+		{"en", 3, "3 files"},
+		{"ru", 1, "1 файл"},
+		{"ru", 3, "3 файла"},
+		{"ru", 25, "25 файлов"},
+		{"ru", 1.5, "1,5 файла"},
+		{"ru", 10000, "10\u00a0000 файлов"},
+		{"cs", 3, "soubory"},
+		{"fr", 1, "1 file"},
+	}
+
+	for _, tc := range cases {
+		cr := c.Lang(ToIndex(tc.lang))
+		if s := cr.Plural("Files", tc.n); s != tc.exp {
+			t.Errorf("%s %v: expected '%s', got '%s'", tc.lang, tc.n, tc.exp, s)
+		}
+	}
+
+	de := c.Lang(ToIndex("de"))
+	if s, exp := de.Plural("Order", 1), "1 Bestellung #{id}"; s != exp {
+		t.Errorf("expected '%s', got '%s'", exp, s)
+	}
+	if s, exp := de.Plural("Order", 2), "2 Bestellungen in C#, # und 2"; s != exp {
+		t.Errorf("expected '%s', got '%s'", exp, s)
+	}
+
+	cr := c.Lang(ToIndex("ru"))
+	if s := cr.Plural("Folders", 2); s != "Folders" {
+		t.Errorf("expected 'Folders', got '%s'", s)
+	}
+	if s := cr.Plural("Unknown", 2); s != "Unknown"+NotFoundMarker {
+		t.Errorf("expected 'Unknown%s', got '%s'", NotFoundMarker, s)
+	}
+
+	buf, err := cr.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kv map[string]ResponseItem
+	if err := json.Unmarshal(buf, &kv); err != nil {
+		t.Fatal(err)
+	}
+	ri := kv["Files"]
+	if len(ri.Plural) != 4 || ri.Plural[PluralFew] != "# файла" || ri.Value != "# файла" {
+		t.Errorf("unexpected plural forms in %s", buf)
+	}
+	if _, ok := kv["Files[one]"]; ok {
+		t.Errorf("unexpected key with category in %s", buf)
+	}
+}
+
+func TestOrdinal(t *testing.T) {
+	cases := []struct {
+		lang string
+		n    interface{}
+		exp  PluralCategory
+	}{
+		{"en", 1, PluralOne},
+		{"en", 2, PluralTwo},
+		{"en", 3, PluralFew},
+		{"en", 4, PluralOther},
+		{"en", 11, PluralOther},
+		{"en", 12, PluralOther},
+		{"en", 13, PluralOther},
+		{"en", 21, PluralOne},
+		{"en", 102, PluralTwo},
+		{"en-GB", 23, PluralFew},
+		{"fr", 1, PluralOne},
+		{"fr", 2, PluralOther},
+		{"it", 8, PluralMany},
+		{"sv", 22, PluralOne},
+		{"hu", 5, PluralOne},
+		{"uk", 43, PluralFew},
+		{"ru", 1, PluralOther},
+		{"de", 1, PluralOther},
+	}
+
+	for _, tc := range cases {
+		pc, err := Ordinal(ToIndex(tc.lang), tc.n)
+		if err != nil {
+			t.Errorf("%s %v: %v", tc.lang, tc.n, err)
+			continue
+		}
+		if pc != tc.exp {
+			t.Errorf("%s %v: expected %s, got %s", tc.lang, tc.n, tc.exp, pc)
+		}
+	}
+
+	fsys := fstest.MapFS{
+		"en.i18n": {Data: []byte("Place[ordinal:one]=#st place\nPlace[ordinal:two]=#nd place\nPlace[ordinal:few]=#rd place\nPlace[ordinal:other]=#th place\n" +
+			"Rank={n, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}\n")},
+		"de.i18n": {Data: []byte("Place=#. Platz\nRank={n, selectordinal, other {#.}}\n")},
+	}
+
+	c := New(WithPrimaryLanguage(ToIndex("en")), WithMessageFormat())
+	if err := c.AddFileByMaskFS(fsys, ".", "*.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	texts := []struct {
+		lang string
+		n    int
+		exp  string
+		rank string
+	}{
+		{"en", 1, "1st place", "1st"},
+		{"en", 22, "22nd place", "22nd"},
+		{"en", 13, "13th place", "13th"},
+		{"en", 103, "103rd place", "103rd"},
+		{"de", 3, "3. Platz", "3."},
+	}
+
+	for _, tc := range texts {
+		cr := c.Lang(ToIndex(tc.lang))
+		if s := cr.Ordinal("Place", tc.n); s != tc.exp {
+			t.Errorf("%s %d: expected '%s', got '%s'", tc.lang, tc.n, tc.exp, s)
+		}
+		if s, err := cr.Format("Rank", map[string]int{"n": tc.n}); err != nil || s != tc.rank {
+			t.Errorf("%s %d: expected '%s', got '%s' (%v)", tc.lang, tc.n, tc.rank, s, err)
+		}
+	}
+
+	cr := c.Lang(ToIndex("en"))
+	buf, err := cr.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var kv map[string]ResponseItem
+	if err := json.Unmarshal(buf, &kv); err != nil {
+		t.Fatal(err)
+	}
+	if ri := kv["Place"]; len(ri.Ordinal) != 4 || ri.Plural != nil || ri.Value != "#th place" {
+		t.Errorf("unexpected ordinal forms in %s", buf)
+	}
+}
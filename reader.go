@@ -0,0 +1,223 @@
+package language
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// lineReader reads logical lines of a translation file. A line ending
+// with a space and an odd number of backslashes continues on the next line:
+// the backslash is removed and the next line is appended without leading spaces.
+type lineReader struct {
+	scanner *bufio.Scanner
+	// lineNo is the number of the last physical line read.
+	lineNo int
+	// start is the number of the first physical line of the last logical line.
+	start int
+	// check is called for every physical line if assigned.
+	check func(lineNo int, line string)
+}
+
+func newLineReader(r io.Reader) *lineReader {
+	return &lineReader{scanner: bufio.NewScanner(r)}
+}
+
+// next returns the next logical line, false at the end of file or on error.
+func (r *lineReader) next() (string, bool) {
+	if !r.scanner.Scan() {
+		return "", false
+	}
+	r.lineNo++
+	r.start = r.lineNo
+
+	line := r.scanner.Text()
+	if r.lineNo == 1 {
+		line = strings.TrimPrefix(line, byteOrderMark)
+	}
+	r.checkLine(line)
+	for continues(line) && r.scanner.Scan() {
+		r.lineNo++
+		next := r.scanner.Text()
+		r.checkLine(next)
+		line = line[:len(line)-1] + strings.TrimLeft(next, " \t")
+	}
+	if continues(line) {
+		// the last line of the file
+		line = line[:len(line)-1]
+	}
+	return line, true
+}
+
+func (r *lineReader) checkLine(line string) {
+	if r.check != nil {
+		r.check(r.lineNo, line)
+	}
+}
+
+// err returns the first error met by the reader.
+func (r *lineReader) err() error {
+	return r.scanner.Err()
+}
+
+// continues returns true if line ends with an odd number of backslashes
+// following a space or a tab, so Windows paths like C:\Temp\ end the line.
+func continues(line string) bool {
+	i := len(line) - 1
+	for i >= 0 && line[i] == '\\' {
+		i--
+	}
+	n := len(line) - 1 - i
+	return n%2 == 1 && i >= 0 && (line[i] == ' ' || line[i] == '\t')
+}
+
+// opensBlock returns true if value v starts a multiline value: it starts
+// with '{' and its braces are not balanced.
+func opensBlock(v string) bool {
+	if !strings.HasPrefix(v, "{") {
+		return false
+	}
+	_, closed := closingBrace(v[1:], 1)
+	return closed == -1
+}
+
+// closingBrace scans s with initial brace depth and returns the depth at
+// the end of s and the position of the brace making depth zero, -1 if
+// there is no such brace.
+func closingBrace(s string, depth int) (int, int) {
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return 0, i
+			}
+		}
+	}
+	return depth, -1
+}
+
+// readBlock reads a multiline value started by first, the rest of the line
+// after '='. The value is text between the outer braces without spaces
+// following the opening and preceding the closing brace, lines holding
+// only the opening or the closing brace are not part of the value.
+// It returns the value and the text after the closing brace.
+func readBlock(r *lineReader, first string) (value, rest string, ok bool) {
+	depth, _ := closingBrace(first[1:], 1)
+
+	var parts []string
+	if s := strings.TrimLeft(first[1:], " \t"); s != "" {
+		parts = append(parts, s)
+	}
+
+	for {
+		line, more := r.next()
+		if !more {
+			return "", "", false
+		}
+
+		var pos int
+		depth, pos = closingBrace(line, depth)
+		if pos == -1 {
+			parts = append(parts, line)
+			continue
+		}
+
+		if s := strings.TrimRight(line[:pos], " \t"); strings.TrimSpace(s) != "" {
+			parts = append(parts, s)
+		}
+		return strings.Join(parts, "\n"), line[pos+1:], true
+	}
+}
+
+// indexUnescaped returns index of the first sep in s not preceded
+// by a backslash escape, -1 if there is no such sep.
+func indexUnescaped(s, sep string) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\':
+			i++
+		case strings.HasPrefix(s[i:], sep):
+			return i
+		}
+	}
+	return -1
+}
+
+// hintIndex returns index of HintSeparator in raw value v, -1 if there is
+// no hint. The separator must follow a space or a tab and not be escaped,
+// so "https://example.com" and "\/\/" are parts of the value.
+func hintIndex(v string) int {
+	for from := 0; from < len(v); {
+		i := indexUnescaped(v[from:], HintSeparator)
+		if i == -1 {
+			return -1
+		}
+		i += from
+		if i > 0 && (v[i-1] == ' ' || v[i-1] == '\t') {
+			return i
+		}
+		from = i + len(HintSeparator)
+	}
+	return -1
+}
+
+// unescape replaces escape sequences of s: \n is a new line, \t is a tab,
+// \uXXXX is a Unicode character, \\, \/, \= and \# are the escaped
+// characters. Other backslashes are kept as they are.
+func unescape(s string) string {
+	return unescapeKeeping(s, "")
+}
+
+// unescapeValue unescapes value v of key k. Values of plural forms keep
+// \\ and \# to tell a literal # from the number, see replacePound.
+func unescapeValue(k, v string) string {
+	if _, _, _, ok := splitPluralKey(k); ok {
+		return unescapeKeeping(v, `\#`)
+	}
+	return unescape(v)
+}
+
+// unescapeKeeping is like unescape but keeps escapes of characters of kept.
+func unescapeKeeping(s, kept string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		i++
+		switch ch := s[i]; {
+		case strings.IndexByte(kept, ch) != -1:
+			sb.WriteByte('\\')
+			sb.WriteByte(ch)
+		case ch == 'n':
+			sb.WriteByte('\n')
+		case ch == 't':
+			sb.WriteByte('\t')
+		case ch == '\\' || ch == '/' || ch == '=' || ch == '#':
+			sb.WriteByte(ch)
+		case ch == 'u':
+			if i+5 <= len(s) {
+				if r, err := strconv.ParseUint(s[i+1:i+5], 16, 32); err == nil {
+					sb.WriteRune(rune(r))
+					i += 4
+					continue
+				}
+			}
+			sb.WriteString(`\u`)
+		default:
+			sb.WriteByte('\\')
+			sb.WriteByte(ch)
+		}
+	}
+	return sb.String()
+}
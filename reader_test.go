@@ -0,0 +1,168 @@
+package language
+
+import (
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestMultilineValues(t *testing.T) {
+	data := strings.Join([]string{
+		"Help={",
+		"Press Save to keep changes.",
+		"",
+		"# this line is a part of the value",
+		"}",
+		"Mail={Dear {name},",
+		"  your order is shipped.}  // e-mail body",
+		"Files={",
+		"{count, plural,",
+		"  one {# file}",
+		"  other {# files}}",
+		"}",
+		"Long=The quick brown fox \\",
+		"    jumps over the lazy dog",
+		"Path=C:\\\\",
+		"Dir=C:\\Temp\\",
+		"Save=Save",
+		"Count={count, plural, one {# item} other {# items}}",
+		"Orders={count, plural,",
+		"  =0 {No orders}",
+		"  other {# orders}}",
+		"Exit=Exit",
+	}, "\n")
+
+	fsys := fstest.MapFS{"en.i18n": {Data: []byte(data)}}
+
+	c := New(WithPrimaryLanguage(ToIndex("en")))
+	if err := c.AddFilesFS(fsys, "en.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	cr := c.Lang(ToIndex("en"))
+	cases := []struct {
+		key string
+		exp string
+	}{
+		{"Help", "Press Save to keep changes.\n\n# this line is a part of the value"},
+		{"Mail", "Dear {name},\n  your order is shipped."},
+		{"Files", "{count, plural,\n  one {# file}\n  other {# files}}"},
+		{"Long", "The quick brown fox jumps over the lazy dog"},
+		{"Path", "C:\\"},
+		{"Dir", "C:\\Temp\\"},
+		{"Save", "Save"},
+		{"Count", "{count, plural, one {# item} other {# items}}"},
+		{"Orders", "count, plural,\n  =0 {No orders}\n  other {# orders}"},
+		{"Exit", "Exit"},
+	}
+	for _, tc := range cases {
+		if v := cr.Value(tc.key); v != tc.exp {
+			t.Errorf("%s: expected %q, got %q", tc.key, tc.exp, v)
+		}
+	}
+	if h := cr.Hint("Mail"); h != "e-mail body" {
+		t.Errorf("expected hint %q, got %q", "e-mail body", h)
+	}
+
+	c = New(WithStrictMode())
+	if err := c.AddFilesFS(fsys, "en.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Errorf("unexpected strict mode error: %v", err)
+	}
+
+	c = New(WithPrimaryLanguage(ToIndex("en")), WithMessageFormat())
+	if err := c.AddFilesFS(fsys, "en.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+	en := c.Lang(ToIndex("en"))
+	if s, err := en.Format("Files", map[string]interface{}{"count": 2}); err != nil || s != "2 files" {
+		t.Errorf("expected %q, got %q (%v)", "2 files", s, err)
+	}
+	if s, err := en.Format("Orders", map[string]interface{}{"count": 0}); err != nil || s != "No orders" {
+		t.Errorf("expected %q, got %q (%v)", "No orders", s, err)
+	}
+	if v := en.Value("Mail"); v != "Dear {name},\n  your order is shipped." {
+		t.Errorf("expected braces of Mail to be stripped, got %q", v)
+	}
+
+	broken := fstest.MapFS{"en.i18n": {Data: []byte("Save=Save\nHelp={\nno closing brace\n")}}
+	c = New()
+	if err := c.AddFilesFS(broken, "en.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	err := c.ReadRegisteredFiles()
+	if err == nil || !strings.HasPrefix(err.Error(), "en.i18n:2: Help:") {
+		t.Errorf("expected unterminated value error, got %v", err)
+	}
+}
+
+func TestEscapes(t *testing.T) {
+	data := strings.Join([]string{
+		"Site=See https://example.com/docs?a=b // link to the docs",
+		"Api=https://api.example.com",
+		"Slashes=a\\/\\/b // escaped slashes",
+		"Comment= // only a hint",
+		"Protocol=//cdn.example.com/app.js",
+		"Temp=C:\\\\Temp\\\\new // Windows path",
+		"Share=\\\\\\\\server\\\\share",
+		"Raw=C:\\Program Files\\App",
+		"Lines=first\\nsecond\\tindented",
+		"Eq=\\=x",
+		"Hash=\\# not a comment",
+		"Euro=\\u20ac 5",
+		"Bad=\\u20",
+		"Key\\=Part=value",
+		"Tag[one]=\\# # in C:\\\\",
+		"Tag[other]=\\# # in C:\\\\",
+	}, "\n")
+
+	fsys := fstest.MapFS{"en.i18n": {Data: []byte(data)}}
+	c := New(WithPrimaryLanguage(ToIndex("en")))
+	if err := c.AddFilesFS(fsys, "en.i18n"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	cr := c.Lang(ToIndex("en"))
+	cases := []struct {
+		key  string
+		exp  string
+		hint string
+	}{
+		{"Site", "See https://example.com/docs?a=b", "link to the docs"},
+		{"Api", "https://api.example.com", ""},
+		{"Slashes", "a//b", "escaped slashes"},
+		{"Comment", "", "only a hint"},
+		{"Protocol", "//cdn.example.com/app.js", ""},
+		{"Temp", "C:\\Temp\\new", "Windows path"},
+		{"Share", "\\\\server\\share", ""},
+		{"Raw", "C:\\Program Files\\App", ""},
+		{"Lines", "first\nsecond\tindented", ""},
+		{"Eq", "=x", ""},
+		{"Hash", "# not a comment", ""},
+		{"Euro", "€ 5", ""},
+		{"Bad", "\\u20", ""},
+		{"Key=Part", "value", ""},
+	}
+	for _, tc := range cases {
+		if v := cr.Value(tc.key); v != tc.exp {
+			t.Errorf("%s: expected %q, got %q", tc.key, tc.exp, v)
+		}
+		if h := cr.Hint(tc.key); h != tc.hint {
+			t.Errorf("%s: expected hint %q, got %q", tc.key, tc.hint, h)
+		}
+	}
+	if v := cr.Plural("Tag", 3); v != "# 3 in C:\\" {
+		t.Errorf("expected escaped # kept in plural form, got %q", v)
+	}
+}
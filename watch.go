@@ -0,0 +1,180 @@
+package language
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// stamp describes a state of a watched file.
+type stamp struct {
+	name    string
+	modTime time.Time
+	size    int64
+	missing bool
+}
+
+type watcher struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// ListenFileChange starts watching of registered files, files included by them
+// and files in custom directories.
+// When any of them is changed, added or removed translations are reloaded by
+// ReadRegisteredFiles. A new translation set replaces the previous one at once,
+// thus concurrent requests see either old or new translations.
+//
+// Reload errors are passed to the function assigned by WithReloadErrorHandler
+// or written to the standard logger, previous translations stay in use.
+//
+// Watching stops when Close is called.
+func (c *Container) ListenFileChange() error {
+	return c.ListenFileChangeContext(context.Background())
+}
+
+// ListenFileChangeContext is like ListenFileChange but watching also stops
+// when ctx is done.
+func (c *Container) ListenFileChangeContext(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.watcher != nil {
+		return errors.New("container already listens file changes")
+	}
+
+	if c.cfg.watchInterval <= 0 {
+		return errors.New("watch interval must be positive")
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	w := &watcher{
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+	c.watcher = w
+
+	last := c.watchedStamps()
+	go c.watch(ctx, w, last)
+	return nil
+}
+
+// Close stops watching started by ListenFileChange or ListenFileChangeContext.
+// It waits until running reload, if any, is finished.
+func (c *Container) Close() error {
+	c.mu.Lock()
+	w := c.watcher
+	c.mu.Unlock()
+
+	if w == nil {
+		return nil
+	}
+
+	w.cancel()
+	<-w.done
+	return nil
+}
+
+func (c *Container) watch(ctx context.Context, w *watcher, last []stamp) {
+	ticker := time.NewTicker(c.cfg.watchInterval)
+	defer func() {
+		ticker.Stop()
+		c.mu.Lock()
+		if c.watcher == w {
+			c.watcher = nil
+		}
+		c.mu.Unlock()
+		close(w.done)
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.Lock()
+		curr := c.watchedStamps()
+		c.mu.Unlock()
+
+		if equalStamps(last, curr) {
+			continue
+		}
+		last = curr
+
+		if err := c.ReadRegisteredFiles(); err != nil {
+			c.reloadError(err)
+		}
+	}
+}
+
+// reloadError passes err to the reload error handler, to the standard logger
+// if the handler is not assigned.
+func (c *Container) reloadError(err error) {
+	if c.cfg.reloadErrorHandler != nil {
+		c.cfg.reloadErrorHandler(err)
+		return
+	}
+	log.Printf("language: reload translations: %v", err)
+}
+
+// watchedStamps returns state of all registered files, files included by them
+// and translation files found in custom directories. Caller must hold c.mu.
+func (c *Container) watchedStamps() []stamp {
+	res := make([]stamp, 0, len(c.files)+len(c.included))
+
+	for i := range c.files {
+		fi, err := c.files[i].stat()
+		res = append(res, newStamp(c.files[i].fullName, fi, err))
+	}
+
+	for i := range c.included {
+		fi, err := c.included[i].stat()
+		res = append(res, newStamp(c.included[i].fullName, fi, err))
+	}
+
+	for _, dir := range c.customDirs {
+		dirEntries, err := os.ReadDir(dir)
+		if err != nil {
+			res = append(res, stamp{name: dir, missing: true})
+			continue
+		}
+		for _, de := range dirEntries {
+			if de.IsDir() || !strings.HasSuffix(de.Name(), FileExtension) {
+				continue
+			}
+			fullName := filepath.Join(dir, de.Name())
+			fi, err := os.Stat(fullName)
+			res = append(res, newStamp(fullName, fi, err))
+		}
+	}
+	return res
+}
+
+func newStamp(name string, fi fs.FileInfo, err error) stamp {
+	if err != nil {
+		return stamp{name: name, missing: true}
+	}
+	return stamp{name: name, modTime: fi.ModTime(), size: fi.Size()}
+}
+
+func equalStamps(a, b []stamp) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].name != b[i].name ||
+			a[i].missing != b[i].missing ||
+			a[i].size != b[i].size ||
+			!a[i].modTime.Equal(b[i].modTime) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,160 @@
+package language
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestListenFileChange(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "en.i18n")
+	write := func(content string, mt time.Time) {
+		if err := os.WriteFile(fname, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(fname, mt, mt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	now := time.Now()
+	write("Save=Save\n", now)
+
+	errs := make(chan error, 1)
+	c := New(
+		WithWatchInterval(5*time.Millisecond),
+		WithReloadErrorHandler(func(err error) {
+			select {
+			case errs <- err:
+			default:
+			}
+		}),
+	)
+	if err := c.AddFiles(fname); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.ListenFileChange(); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if err := c.ListenFileChange(); err == nil {
+		t.Fatal("expected error on second ListenFileChange")
+	}
+
+	cr := c.Lang(ToIndex("en"))
+	write("Save=Store\n", now.Add(time.Second))
+
+	deadline := time.Now().Add(5 * time.Second)
+	for cr.Value("Save") != "Store" {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected 'Store', got '%s'", cr.Value("Save"))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := os.Remove(fname); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-errs:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reload error expected")
+	}
+
+	if v := cr.Value("Save"); v != "Store" {
+		t.Fatalf("previous translations expected, got '%s'", v)
+	}
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ListenFileChange(); err != nil {
+		t.Fatalf("ListenFileChange after Close: %v", err)
+	}
+}
+
+func TestListenFileChangeContext(t *testing.T) {
+	dir := t.TempDir()
+	fname := filepath.Join(dir, "en.i18n")
+	if err := os.WriteFile(fname, []byte("Save=Save\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf syncBuffer
+	log.SetOutput(&buf)
+	defer log.SetOutput(os.Stderr)
+
+	c := New(WithWatchInterval(5 * time.Millisecond))
+	if err := c.AddFiles(fname); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.ReadRegisteredFiles(); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := c.ListenFileChangeContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// without a reload error handler errors are logged
+	if err := os.Remove(fname); err != nil {
+		t.Fatal(err)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if strings.Contains(buf.String(), "language: reload translations:") {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("reload error expected in log")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	deadline = time.Now().Add(5 * time.Second)
+	for {
+		c.mu.Lock()
+		stopped := c.watcher == nil
+		c.mu.Unlock()
+		if stopped {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("expected watching to stop when context is done")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// syncBuffer is bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}